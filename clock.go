@@ -0,0 +1,230 @@
+// Clock.go provides the clock used by the mocked Stdin for all waiting. The real clock is based on the time package
+// and therefore also works inside testing/synctest bubbles. The fake clock is advanced manually, so that delays
+// are deterministic and do not slow down tests.
+//
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock
+
+// Import go standard library packages
+import (
+	"context" // context
+	"sync"    // sync
+	"time"    // time
+)
+
+// Clock is the interface for the clock used by the mocked Stdin for all waiting.
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+	// NewTimer returns a new timer, which sends the current time on its channel after at least duration d
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is the interface for a timer retrieved from a Clock.
+type Timer interface {
+	// C returns the channel on which the current time is sent, when the timer fires
+	C() <-chan time.Time
+	// Stop prevents the timer from firing. It returns false, if the timer already fired or has been stopped.
+	Stop() bool
+}
+
+// realClock is the clock based on the time package.
+type realClock struct{}
+
+// realTimer is a timer based on the time package.
+type realTimer struct {
+	t *time.Timer // Timer of the time package
+}
+
+// Now returns the current time.
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// NewTimer returns a new timer of the time package for d.
+func (realClock) NewTimer(d time.Duration) Timer {
+	return &realTimer{t: time.NewTimer(d)}
+}
+
+// C returns the channel of the timer.
+func (t *realTimer) C() <-chan time.Time {
+	return t.t.C
+}
+
+// Stop stops the timer.
+func (t *realTimer) Stop() bool {
+	return t.t.Stop()
+}
+
+// FakeClock is a clock, which is advanced manually with Advance. It holds the current time and the pending timers. A channel
+// is closed and renewed each time a timer is added or removed, so that BlockUntil can wait for pending timers.
+type FakeClock struct {
+	now     time.Time     // Current time
+	timers  []*fakeTimer  // Pending timers
+	changed chan struct{} // Closed and renewed on each change of the pending timers
+	mu      sync.Mutex    // Mutex
+}
+
+// fakeTimer is a timer retrieved from a FakeClock.
+type fakeTimer struct {
+	clock *FakeClock     // Clock of the timer
+	at    time.Time      // Time at which the timer fires
+	c     chan time.Time // Channel of the timer
+}
+
+// NewFakeClock returns a new fake clock with the current time set to t.
+func NewFakeClock(t time.Time) *FakeClock {
+	return &FakeClock{now: t, changed: make(chan struct{})}
+}
+
+// Now returns the current time of the fake clock.
+func (c *FakeClock) Now() time.Time {
+	// Lock the mutex
+	c.mu.Lock()
+	// Defer unlocking the mutex
+	defer c.mu.Unlock()
+	// Return the current time
+	return c.now
+}
+
+// NewTimer returns a new timer, which fires when the fake clock has been advanced by at least d. If d is not positive,
+// the timer fires immediately.
+func (c *FakeClock) NewTimer(d time.Duration) Timer {
+	// Lock the mutex
+	c.mu.Lock()
+	// Defer unlocking the mutex
+	defer c.mu.Unlock()
+	// Retrieve a new timer
+	t := &fakeTimer{clock: c, at: c.now.Add(d), c: make(chan time.Time, 1)}
+	// Fire the timer immediately, if d is not positive
+	if d <= 0 {
+		t.c <- c.now
+		return t
+	}
+	// Add the timer to the pending timers
+	c.timers = append(c.timers, t)
+	// Notify the change of the pending timers
+	c.notify()
+	// Return the timer
+	return t
+}
+
+// Advance advances the fake clock by d and fires all pending timers, which are due.
+func (c *FakeClock) Advance(d time.Duration) {
+	// Lock the mutex
+	c.mu.Lock()
+	// Defer unlocking the mutex
+	defer c.mu.Unlock()
+	// Advance the current time by d
+	c.now = c.now.Add(d)
+	// Retrieve the timers which are not due
+	pending := c.timers[:0]
+	for _, t := range c.timers {
+		// Fire the timer, if due
+		if !t.at.After(c.now) {
+			t.c <- c.now
+			continue
+		}
+		// Keep the timer, if not due
+		pending = append(pending, t)
+	}
+	// Notify the change of the pending timers, if any timer fired
+	if len(pending) != len(c.timers) {
+		c.notify()
+	}
+	// Set the pending timers
+	c.timers = pending
+}
+
+// Waiters returns the number of pending timers.
+func (c *FakeClock) Waiters() int {
+	// Lock the mutex
+	c.mu.Lock()
+	// Defer unlocking the mutex
+	defer c.mu.Unlock()
+	// Return the number of pending timers
+	return len(c.timers)
+}
+
+// BlockUntil waits until at least n timers are pending. It returns an error if the context is done before.
+func (c *FakeClock) BlockUntil(ctx context.Context, n int) error {
+	for {
+		// Lock the mutex
+		c.mu.Lock()
+		// Retrieve the number of pending timers and the notification channel
+		w, ch := len(c.timers), c.changed
+		// Unlock the mutex
+		c.mu.Unlock()
+		// Return nil, if at least n timers are pending
+		if w >= n {
+			return nil
+		}
+		select {
+		// Return an error, if the context is done
+		case <-ctx.Done():
+			return ctx.Err()
+		// Check again, if the pending timers changed
+		case <-ch:
+		}
+	}
+}
+
+// notify closes and renews the notification channel. The mutex must be locked.
+func (c *FakeClock) notify() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// C returns the channel of the timer.
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+// Stop removes the timer from the pending timers of the fake clock. It returns false, if the timer already fired or has been stopped.
+func (t *fakeTimer) Stop() bool {
+	// Lock the mutex
+	t.clock.mu.Lock()
+	// Defer unlocking the mutex
+	defer t.clock.mu.Unlock()
+	// Remove the timer from the pending timers
+	for i, p := range t.clock.timers {
+		if p == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+			t.clock.notify()
+			return true
+		}
+	}
+	// Return false, if the timer is not pending
+	return false
+}
+
+// Clock sets the clock used by the mocked Stdin for all waiting to c. If c is nil, the real clock based on the time package is used,
+// which is the default. Clock returns an error if the mocked Stdin is executing.
+func (stdin *MockStdin) Clock(c Clock) error {
+	// Return an error if mocked Stdin is executing
+	if e := stdin.notRunning(); e != nil {
+		return e
+	}
+	// Set the clock to c
+	stdin.clock.Set(c)
+	// Return nil
+	return nil
+}
+
+// WithClock returns an option to set the clock used by the mocked Stdin for all waiting to c. See Clock.
+func WithClock(c Clock) Option {
+	return func(stdin *MockStdin) error {
+		return stdin.Clock(c)
+	}
+}
+
+// now returns the clock used by the mocked Stdin, which is the real clock if not set.
+func (stdin *MockStdin) now() Clock {
+	// Return the real clock, if the clock is not set
+	if c := stdin.clock.Get(); c != nil {
+		return c
+	}
+	return realClock{}
+}
@@ -0,0 +1,58 @@
+//go:build go1.25
+
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock_test
+
+// Import go standard library packages as well as tserr and tsmock
+import (
+	"context"          // context
+	"io"               // io
+	"os"               // os
+	"testing"          // testing
+	"testing/synctest" // synctest
+	"time"             // time
+
+	"github.com/thorstenrie/tserr"  // tserr
+	"github.com/thorstenrie/tsmock" // tsmock
+)
+
+// TestSynctestDelay tests the delay of the mocked Stdin with the real clock inside a testing/synctest bubble. The test fails if the
+// delay for each line does not elapse in the bubble or if the received input does not equal the contents.
+func TestSynctestDelay(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		// Retrieve a new mocked Stdin with a delay of one hour
+		stdin, e := tsmock.NewStdin(tsmock.WithDelay(time.Hour), tsmock.WithVisibility(false), tsmock.WithString(contents))
+		// The test fails if NewStdin returns an error
+		if e != nil {
+			t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+		}
+		// Retrieve the start time in the bubble
+		start := time.Now()
+		// Mock Stdin
+		if e := stdin.Run(context.Background()); e != nil {
+			// The test fails if Run returns an error
+			t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+		}
+		// Wait for the delay of each line to elapse in the bubble
+		time.Sleep(5*time.Hour + time.Second)
+		// Wait for stdin to finish
+		synctest.Wait()
+		// Read all input from os.Stdin
+		b, _ := io.ReadAll(os.Stdin)
+		// Restore Stdin
+		if e := stdin.Restore(); e != nil {
+			// The test fails if Restore returns an error
+			t.Error(tserr.Op(&tserr.OpArgs{Op: "Restore", Fn: "Stdin", Err: e}))
+		}
+		// The test fails if the received input does not equal the contents
+		if string(b) != contents {
+			t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "os.Stdin", Want: contents, Actual: string(b)}))
+		}
+		// The test fails if the delay did not elapse in the bubble
+		if d := time.Since(start); d < 5*time.Hour {
+			t.Error(tserr.Higher(&tserr.HigherArgs{Var: "duration", Actual: int64(d), LowerBound: int64(5 * time.Hour)}))
+		}
+	})
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock_test
+
+// Import go standard library packages as well as tserr and tsmock
+import (
+	"context" // context
+	"io"      // io
+	"os"      // os
+	"testing" // testing
+	"time"    // time
+
+	"github.com/thorstenrie/tserr"  // tserr
+	"github.com/thorstenrie/tsmock" // tsmock
+)
+
+// TestFakeClock tests that a timer of the fake clock fires only after the fake clock has been advanced
+// by its duration and that a stopped timer does not fire. The test fails if a timer fires too early or not at all.
+func TestFakeClock(t *testing.T) {
+	// Retrieve a new fake clock
+	c := tsmock.NewFakeClock(time.Time{})
+	// Retrieve a timer for one second and a timer to be stopped
+	t1, t2 := c.NewTimer(time.Second), c.NewTimer(time.Second)
+	// The test fails if the number of pending timers is not two
+	if w := c.Waiters(); w != 2 {
+		t.Error(tserr.Equal(&tserr.EqualArgs{Var: "Waiters", Actual: int64(w), Want: 2}))
+	}
+	// The test fails if Stop returns false for a pending timer
+	if !t2.Stop() {
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "Stop", Actual: "false", Want: "true"}))
+	}
+	// Advance the fake clock by less than a second
+	c.Advance(time.Second - 1)
+	select {
+	// The test fails if the timer fired too early
+	case <-t1.C():
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "C", Actual: "fired", Want: "pending"}))
+	default:
+	}
+	// Advance the fake clock to one second
+	c.Advance(1)
+	select {
+	// The test fails if the fired time does not equal one second
+	case now := <-t1.C():
+		if now != (time.Time{}).Add(time.Second) {
+			t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "time", Want: (time.Time{}).Add(time.Second).String(), Actual: now.String()}))
+		}
+	// The test fails if the timer did not fire
+	default:
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "C", Actual: "pending", Want: "fired"}))
+	}
+	select {
+	// The test fails if the stopped timer fired
+	case <-t2.C():
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "C", Actual: "fired", Want: "stopped"}))
+	default:
+	}
+}
+
+// TestFakeClockDelay tests the delay of the mocked Stdin with a fake clock. The mocked Stdin writes all input only after the fake clock
+// has been advanced by the delay for each line. The test fails if the fake clock was not advanced by the delay for each line or if the
+// received input does not equal the contents.
+func TestFakeClockDelay(t *testing.T) {
+	// Retrieve a new fake clock
+	c := tsmock.NewFakeClock(time.Time{})
+	// Retrieve a new mocked Stdin with a delay of one hour and the fake clock
+	stdin, e := tsmock.NewStdin(tsmock.WithClock(c), tsmock.WithDelay(time.Hour), tsmock.WithVisibility(false), tsmock.WithString(contents))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Advance the fake clock by one hour for each line
+	for i := 0; i < 5; i++ {
+		// Wait for stdin to wait for the delay
+		if e := c.BlockUntil(context.Background(), 1); e != nil {
+			t.Fatal(e)
+		}
+		// Advance the fake clock by the delay
+		c.Advance(time.Hour)
+	}
+	// Read all input from os.Stdin
+	b, _ := io.ReadAll(os.Stdin)
+	// Restore Stdin
+	if e := stdin.Restore(); e != nil {
+		// The test fails if Restore returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Restore", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if the received input does not equal the contents
+	if string(b) != contents {
+		t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "os.Stdin", Want: contents, Actual: string(b)}))
+	}
+	// The test fails if the fake clock was not advanced by the delay for each line
+	if d := c.Now().Sub(time.Time{}); d != 5*time.Hour {
+		t.Error(tserr.Equal(&tserr.EqualArgs{Var: "duration", Actual: int64(d), Want: int64(5 * time.Hour)}))
+	}
+}
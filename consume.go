@@ -0,0 +1,228 @@
+// Consume.go provides the consumption of the input of the mocked Stdin by the program. Unconsumed reports the lines and bytes
+// written into the mocked Stdin, but not read by the program. In strict mode, Restore returns an error, if input was left unread,
+// and the run fails, if the program reads past the end of the input. The bytes left in the buffer of the pipe are only reported
+// on platforms supporting it. Otherwise, Unconsumed reports zero. In lockstep mode, the next line is only written after the program
+// read the previous line.
+//
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock
+
+// Import go standard library packages
+import (
+	"context" // context
+	"errors"  // errors
+	"fmt"     // fmt
+	"sort"    // sort
+	"time"    // time
+)
+
+// ErrOverRead is returned in strict mode, if the program reads past the end of the input of the mocked Stdin.
+var ErrOverRead = errors.New("input read past the end")
+
+// pollInterval is the interval for polling the consumption of the input by the program.
+const pollInterval = time.Millisecond
+
+// written holds the byte offsets after each line written into the mocked Stdin and the number of bytes written.
+type written struct {
+	ends  []int64 // Byte offsets after the newline of each line written
+	bytes int64   // Number of bytes written
+}
+
+// unread holds the number of lines and bytes written into the mocked Stdin, but not read by the program.
+type unread struct {
+	lines int   // Number of lines, including a last line without a newline
+	bytes int64 // Number of bytes
+}
+
+// Strict enables the strict mode of the mocked Stdin, if s is true. In strict mode, Restore returns an error wrapping ErrUnconsumed,
+// if input was not written completely or was left unread by the program. After all input has been written into a pipe, the mocked Stdin
+// waits for the program to read past the end of the input before sending end-of-file. If the program does, the run fails with an error
+// wrapping ErrOverRead. Otherwise, the run ends with Restore. To detect a waiting read, the read end of the pipe is opened in blocking mode,
+// which is only supported on Linux, and polled every millisecond of the clock. Strict returns an error if the mocked Stdin is executing.
+func (stdin *MockStdin) Strict(s bool) error {
+	// Return an error if mocked Stdin is executing
+	if e := stdin.notRunning(); e != nil {
+		return e
+	}
+	// Set strict mode to s
+	stdin.strict.Set(s)
+	// Return nil
+	return nil
+}
+
+// WithStrict returns an option to enable the strict mode of the mocked Stdin, if s is true. See Strict.
+func WithStrict(s bool) Option {
+	return func(stdin *MockStdin) error {
+		return stdin.Strict(s)
+	}
+}
+
+// Lockstep enables the lockstep mode of the mocked Stdin, if l is true. In lockstep mode, the next line is only written after the program
+// read all input written so far, which is detected by polling the bytes left in the buffer of the pipe or pseudo-terminal every millisecond of
+// the clock. With a FakeClock, the clock must be advanced to detect the read. The delay is applied after the line has been read. On platforms not reporting the bytes left in the buffer, the run fails. Lockstep returns an error if the mocked
+// Stdin is executing.
+func (stdin *MockStdin) Lockstep(l bool) error {
+	// Return an error if mocked Stdin is executing
+	if e := stdin.notRunning(); e != nil {
+		return e
+	}
+	// Set lockstep mode to l
+	stdin.lockstep.Set(l)
+	// Return nil
+	return nil
+}
+
+// WithLockstep returns an option to enable the lockstep mode of the mocked Stdin, if l is true. See Lockstep.
+func WithLockstep(l bool) Option {
+	return func(stdin *MockStdin) error {
+		return stdin.Lockstep(l)
+	}
+}
+
+// Unconsumed returns the number of lines and bytes written into the mocked Stdin, but not read by the program. A last line without
+// a newline is counted as line. After Restore, it returns the numbers at the time of Restore.
+func (stdin *MockStdin) Unconsumed() (int, int64) {
+	// Retrieve the numbers at the time of Restore, if the pipe is closed
+	l := stdin.left.Get()
+	// Retrieve the current numbers, if the pipe is open
+	if stdin.r != nil {
+		l = stdin.unread()
+	}
+	// Return the number of lines and bytes
+	return l.lines, l.bytes
+}
+
+// record adds the lines and bytes of p to the input written into the mocked Stdin.
+func (stdin *MockStdin) record(p []byte) {
+	stdin.wr.Update(func(w written) (written, error) {
+		// Add the byte offset after each newline in p
+		for k, b := range p {
+			if b == '\n' {
+				w.ends = append(w.ends, w.bytes+int64(k)+1)
+			}
+		}
+		// Add the number of bytes of p
+		w.bytes += int64(len(p))
+		// Return the updated input written
+		return w, nil
+	})
+}
+
+// unread returns the number of lines and bytes written into the mocked Stdin, but not read by the program. It returns zero,
+// if the bytes left in the buffer of the pipe cannot be retrieved.
+func (stdin *MockStdin) unread() unread {
+	// Retrieve the bytes left in the buffer of the pipe
+	b, e := buffered(stdin.r)
+	// Return zero, if the bytes left cannot be retrieved
+	if e != nil {
+		return unread{}
+	}
+	// Retrieve the input written
+	w := stdin.wr.Get()
+	// Limit the bytes left to the bytes recorded, since a write may not be recorded yet
+	b = min(b, w.bytes)
+	// Number of bytes read by the program
+	c := w.bytes - b
+	// Count the lines ending after the bytes read by the program
+	l := len(w.ends) - sort.Search(len(w.ends), func(k int) bool { return w.ends[k] > c })
+	// Count a last line without a newline, if not read completely
+	if (b > 0) && ((len(w.ends) == 0) || (w.ends[len(w.ends)-1] < w.bytes)) {
+		l++
+	}
+	// Return the number of lines and bytes
+	return unread{lines: l, bytes: b}
+}
+
+// consumed stores the lines and bytes left unread before the pipe is closed. In strict mode, it adds an error wrapping ErrUnconsumed,
+// if input was not written completely or was left unread by the program.
+func (stdin *MockStdin) consumed() {
+	// Return if the pipe is closed
+	if stdin.r == nil {
+		return
+	}
+	// Store the lines and bytes left unread
+	l := stdin.unread()
+	stdin.left.Set(l)
+	// Return if strict mode is disabled
+	if !stdin.strict.Get() {
+		return
+	}
+	// Add an error, if the input was not written completely
+	if s := stdin.State(); (s == Armed) || ((s != Idle) && !stdin.Result().Finished) {
+		stdin.fail(fmt.Errorf("%w: input not written completely", ErrUnconsumed))
+	}
+	// Add an error, if input was left unread
+	if l.bytes > 0 {
+		stdin.fail(fmt.Errorf("%w: %d lines, %d bytes", ErrUnconsumed, l.lines, l.bytes))
+	}
+}
+
+// drained waits until the program read all input written into the mocked Stdin. It returns the error of the context, if the
+// context is canceled before, and an error, if the bytes left in the buffer of the pipe cannot be retrieved.
+func (stdin *MockStdin) drained(ctx context.Context) error {
+	// Error retrieving the bytes left in the buffer of the pipe, if any
+	var err error
+	// Wait for the buffer of the pipe to be empty
+	if !stdin.poll(ctx, func() bool {
+		// Retrieve the bytes left in the buffer of the pipe
+		var b int64
+		b, err = buffered(stdin.r)
+		// Stop waiting, if the buffer is empty or the bytes left cannot be retrieved
+		return (err != nil) || (b == 0)
+	}) {
+		// Return the error of the context, if the context is canceled
+		return ctx.Err()
+	}
+	// Return the error, if any
+	return err
+}
+
+// overRead waits for the program to read past the end of the input. It returns true, if a read of the program waits for input with
+// all input consumed. It returns false, if the context is canceled or if a waiting read cannot be detected.
+func (stdin *MockStdin) overRead(ctx context.Context) bool {
+	// Return false, if the read end is not a pipe opened in blocking mode
+	if (stdin.opened != KindPipe) || stdin.tty {
+		return false
+	}
+	// Return false, if the bytes left in the buffer of the pipe cannot be retrieved
+	if _, e := buffered(stdin.r); e != nil {
+		return false
+	}
+	// True, if a waiting read has been detected by the previous poll
+	var waiting bool
+	// Wait for a read with all input consumed, detected by two consecutive polls to skip a read just returning the last input
+	return stdin.poll(ctx, func() bool {
+		// Reset the detection, if input is left in the buffer of the pipe
+		if b, e := buffered(stdin.r); (e != nil) || (b > 0) {
+			waiting = false
+			return false
+		}
+		// Return true, if the program waits for reading in this and the previous poll
+		prev := waiting
+		waiting = waitingRead(stdin.r)
+		return prev && waiting
+	})
+}
+
+// poll calls cond in intervals of pollInterval of the clock until cond returns true. It returns true, if cond returns true,
+// and false, if the context is canceled before.
+func (stdin *MockStdin) poll(ctx context.Context, cond func() bool) bool {
+	// Retrieve the clock
+	c := stdin.now()
+	for !cond() {
+		// Retrieve a timer for the interval from the clock
+		t := c.NewTimer(pollInterval)
+		select {
+		// Return false, if the context is canceled
+		case <-ctx.Done():
+			t.Stop()
+			return false
+		// Call cond again after the interval
+		case <-t.C():
+		}
+	}
+	// Return true
+	return true
+}
@@ -0,0 +1,78 @@
+//go:build linux
+
+// Consume_linux.go provides the detection of reads past the end of the input on Linux. In strict mode, the read end of the
+// pipe is opened again in blocking mode, so that a read of the program waiting for more input blocks in the read system call.
+// A waiting read is detected with the system call of each thread reported in /proc/self/task.
+//
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock
+
+// Import go standard library packages and tserr
+import (
+	"os"      // os
+	"strconv" // strconv
+	"strings" // strings
+	"syscall" // syscall
+
+	"github.com/thorstenrie/tserr" // tserr
+)
+
+// blockingRead returns a new file description of the read end f opened in blocking mode and closes f. It returns an error,
+// if opening the new file description fails.
+func blockingRead(f *os.File) (*os.File, error) {
+	// Retrieve the file descriptor of f without changing its blocking mode
+	fd, e := fileFd(f)
+	// Return an error if retrieving the file descriptor fails
+	if e != nil {
+		return nil, tserr.Op(&tserr.OpArgs{Op: "get file descriptor", Fn: f.Name(), Err: e})
+	}
+	// Open a new file description of f in blocking mode
+	n, e := syscall.Open("/proc/self/fd/"+strconv.Itoa(fd), syscall.O_RDONLY|syscall.O_CLOEXEC, 0)
+	// Return an error if opening fails
+	if e != nil {
+		return nil, tserr.Op(&tserr.OpArgs{Op: "open", Fn: f.Name(), Err: e})
+	}
+	// Close f, which is replaced by the new file description
+	f.Close()
+	// Return the new file description, which is not added to the poller, since it is in blocking mode
+	return os.NewFile(uintptr(n), f.Name()), nil
+}
+
+// waitingRead returns true, if a thread is blocked in the read system call on f. It returns false, if f is nil or if
+// the system calls of the threads cannot be retrieved.
+func waitingRead(f *os.File) bool {
+	// Return false, if f is nil
+	if f == nil {
+		return false
+	}
+	// Retrieve the file descriptor of f
+	fd, e := fileFd(f)
+	// Return false, if retrieving the file descriptor fails
+	if e != nil {
+		return false
+	}
+	// Retrieve the threads of the process
+	ts, e := os.ReadDir("/proc/self/task")
+	// Return false, if retrieving the threads fails
+	if e != nil {
+		return false
+	}
+	// System call number and file descriptor of a blocked read of f
+	nr, arg := strconv.Itoa(syscall.SYS_READ), "0x"+strconv.FormatInt(int64(fd), 16)
+	for _, t := range ts {
+		// Retrieve the system call the thread is blocked in, if any
+		b, e := os.ReadFile("/proc/self/task/" + t.Name() + "/syscall")
+		// Continue with the next thread, if retrieving the system call fails
+		if e != nil {
+			continue
+		}
+		// Return true, if the thread is blocked in the read system call with the file descriptor of f as first argument
+		if s := strings.Fields(string(b)); (len(s) > 1) && (s[0] == nr) && (s[1] == arg) {
+			return true
+		}
+	}
+	// Return false
+	return false
+}
@@ -0,0 +1,221 @@
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock_test
+
+// Import go standard library packages as well as tserr and tsmock
+import (
+	"bufio"   // bufio
+	"context" // context
+	"errors"  // errors
+	"io"      // io
+	"os"      // os
+	"strings" // strings
+	"testing" // testing
+	"time"    // time
+
+	"github.com/thorstenrie/tserr"  // tserr
+	"github.com/thorstenrie/tsmock" // tsmock
+)
+
+// TestUnconsumed tests Unconsumed after the program read the first of three lines. The test fails if Unconsumed does not
+// report the second and third line as unread before and after Restore.
+func TestUnconsumed(t *testing.T) {
+	// Run a new mocked Stdin with three lines
+	stdin := testConsume(false, t)
+	// Read the first line
+	if _, e := io.ReadFull(os.Stdin, make([]byte, len("Aragorn\n"))); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "ReadFull", Fn: "Stdin", Err: e}))
+	}
+	// Wait for all input to be written
+	if e := stdin.Wait(context.Background()); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Wait", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if Unconsumed does not report the second and third line
+	testUnconsumed(stdin, 2, int64(len("Boromir\nGandalf\n")), t)
+	// Restore Stdin. The test fails if Restore returns an error.
+	if e := stdin.Restore(); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Restore", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if Unconsumed does not report the second and third line after Restore
+	testUnconsumed(stdin, 2, int64(len("Boromir\nGandalf\n")), t)
+}
+
+// TestStrictUnconsumed tests Restore in strict mode after the program read the first of three lines. The test fails if
+// Restore does not return an error wrapping ErrUnconsumed.
+func TestStrictUnconsumed(t *testing.T) {
+	// Run a new mocked Stdin with three lines in strict mode
+	stdin := testConsume(true, t)
+	// Read the first line
+	if _, e := io.ReadFull(os.Stdin, make([]byte, len("Aragorn\n"))); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "ReadFull", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if Restore does not return an error wrapping ErrUnconsumed
+	if e := stdin.Restore(); !errors.Is(e, tsmock.ErrUnconsumed) {
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "Restore", Actual: "no ErrUnconsumed", Want: "ErrUnconsumed"}))
+	}
+}
+
+// TestStrictOverRead tests the run in strict mode, if the program reads until end-of-file. The test fails if the program
+// does not receive the three lines and end-of-file or if Wait and Restore do not return an error wrapping ErrOverRead.
+func TestStrictOverRead(t *testing.T) {
+	// Run a new mocked Stdin with three lines in strict mode
+	stdin := testConsume(true, t)
+	// Scan Stdin until end-of-file
+	s, n := bufio.NewScanner(os.Stdin), 0
+	for s.Scan() {
+		n++
+	}
+	// The test fails if the number of lines does not equal 3
+	if n != 3 {
+		t.Error(tserr.Equal(&tserr.EqualArgs{Var: "lines", Actual: int64(n), Want: 3}))
+	}
+	// The test fails if Wait does not return an error wrapping ErrOverRead
+	if e := stdin.Wait(context.Background()); !errors.Is(e, tsmock.ErrOverRead) {
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "Wait", Actual: "no ErrOverRead", Want: "ErrOverRead"}))
+	}
+	// The test fails if Restore does not return an error wrapping ErrOverRead
+	if e := stdin.Restore(); !errors.Is(e, tsmock.ErrOverRead) {
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "Restore", Actual: "no ErrOverRead", Want: "ErrOverRead"}))
+	}
+}
+
+// TestStrictExact tests Restore in strict mode after the program read exactly the three lines. The test fails if Restore
+// returns an error or if Unconsumed does not report zero.
+func TestStrictExact(t *testing.T) {
+	// Run a new mocked Stdin with three lines in strict mode
+	stdin := testConsume(true, t)
+	// Read exactly three lines
+	r := bufio.NewReader(os.Stdin)
+	for i := 0; i < 3; i++ {
+		if _, e := r.ReadString('\n'); e != nil {
+			t.Error(tserr.Op(&tserr.OpArgs{Op: "ReadString", Fn: "Stdin", Err: e}))
+		}
+	}
+	// Restore Stdin. The test fails if Restore returns an error.
+	if e := stdin.Restore(); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Restore", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if Unconsumed does not report zero
+	testUnconsumed(stdin, 0, 0, t)
+}
+
+// TestLockstep tests the lockstep mode with five lines and no delay. The test fails if a single read of the program does not
+// retrieve exactly one line or if the run does not complete.
+func TestLockstep(t *testing.T) {
+	// Input with five lines
+	lines := []string{"Aragorn\n", "Boromir\n", "Gandalf\n", "Gimli\n", "Legolas\n"}
+	// Retrieve a new mocked Stdin in lockstep mode
+	stdin, e := tsmock.NewStdin(tsmock.WithVisibility(false), tsmock.WithLockstep(true), tsmock.WithString(strings.Join(lines, "")))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Defer restoring Stdin
+	defer stdin.Restore()
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Read the input with a buffer larger than the input
+	buf := make([]byte, 4096)
+	for _, l := range lines {
+		n, e := os.Stdin.Read(buf)
+		// The test fails if Read returns an error
+		if e != nil {
+			t.Fatal(tserr.Op(&tserr.OpArgs{Op: "Read", Fn: "Stdin", Err: e}))
+		}
+		// The test fails if the read does not retrieve exactly one line
+		if string(buf[:n]) != l {
+			t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "Read", Actual: string(buf[:n]), Want: l}))
+		}
+	}
+	// The test fails if Wait returns an error
+	if e := stdin.Wait(context.Background()); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Wait", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if the run did not complete
+	if s := stdin.State(); s != tsmock.Completed {
+		t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "State", Actual: s.String(), Want: tsmock.Completed.String()}))
+	}
+}
+
+// TestLockstepClock tests the lockstep mode with a fake clock. The test fails if the second line is written before the clock is
+// advanced, if it is not written after the clock is advanced or if any error occurs.
+func TestLockstepClock(t *testing.T) {
+	// Retrieve a new fake clock
+	c := tsmock.NewFakeClock(time.Now())
+	// Retrieve a new mocked Stdin in lockstep mode with the fake clock
+	stdin, e := tsmock.NewStdin(tsmock.WithVisibility(false), tsmock.WithLockstep(true), tsmock.WithClock(c), tsmock.WithString("Aragorn\nBoromir\n"))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Defer restoring Stdin
+	defer stdin.Restore()
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Read the first line
+	r := bufio.NewReader(os.Stdin)
+	testSendRead(r, "Aragorn\n", t)
+	// Retrieve a context with a timeout of a second
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	// Defer cancel function
+	defer cancel()
+	// Wait for the mocked Stdin to poll with the fake clock. The test fails if BlockUntil returns an error.
+	if e := c.BlockUntil(ctx, 1); e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "BlockUntil", Fn: "FakeClock", Err: e}))
+	}
+	// Wait for a possible write of the second line
+	time.Sleep(20 * time.Millisecond)
+	// The test fails if the second line has been written before the clock is advanced
+	testUnconsumed(stdin, 0, 0, t)
+	// Advance the fake clock by the poll interval
+	c.Advance(time.Millisecond)
+	// The test fails if the second line is not written after the clock is advanced
+	testSendRead(r, "Boromir\n", t)
+}
+
+// testConsume returns a new running mocked Stdin with three lines as input and strict mode set to s.
+// The test fails if retrieving or running the mocked Stdin fails.
+func testConsume(s bool, t *testing.T) *tsmock.MockStdin {
+	// Panic if t is nil
+	if t == nil {
+		panic(tserr.NilPtr())
+	}
+	// Retrieve a new mocked Stdin with three lines in strict mode s
+	stdin, e := tsmock.NewStdin(tsmock.WithVisibility(false), tsmock.WithStrict(s), tsmock.WithString("Aragorn\nBoromir\nGandalf\n"))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Return the running mocked Stdin
+	return stdin
+}
+
+// testUnconsumed tests Unconsumed of stdin to report l lines and b bytes. The test fails if it does not.
+func testUnconsumed(stdin *tsmock.MockStdin, l int, b int64, t *testing.T) {
+	// Panic if t is nil
+	if t == nil {
+		panic(tserr.NilPtr())
+	}
+	// Retrieve the lines and bytes left unread
+	al, ab := stdin.Unconsumed()
+	// The test fails if the number of lines does not equal l
+	if al != l {
+		t.Error(tserr.Equal(&tserr.EqualArgs{Var: "lines", Actual: int64(al), Want: int64(l)}))
+	}
+	// The test fails if the number of bytes does not equal b
+	if ab != b {
+		t.Error(tserr.Equal(&tserr.EqualArgs{Var: "bytes", Actual: ab, Want: b}))
+	}
+}
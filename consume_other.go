@@ -0,0 +1,24 @@
+//go:build !linux
+
+// Consume_other.go provides a fallback for platforms without detection of reads past the end of the input. The read
+// end of the pipe is left unchanged and a waiting read is never detected.
+//
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock
+
+// Import go standard library package os
+import (
+	"os" // os
+)
+
+// blockingRead returns f unchanged, since reads past the end of the input are not detected on this platform.
+func blockingRead(f *os.File) (*os.File, error) {
+	return f, nil
+}
+
+// waitingRead returns false, since reads past the end of the input are not detected on this platform.
+func waitingRead(f *os.File) bool {
+	return false
+}
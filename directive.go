@@ -0,0 +1,190 @@
+// Directive.go provides inline directives in the input of the mocked Stdin. If directives are enabled, lines starting
+// with #! are interpreted as directives instead of being written into Stdin:
+//
+//	#! delay 2s                 sets the delay for the following lines
+//	#! hidden                   does not echo the next line
+//	#! eof                      ends the input, the remaining input is ignored, also with KeepOpen
+//	#! signal INT               sends the signal to the current process
+//	#! wait-output "Password:"  waits until the output set with Output contains the text
+//
+// Directives are interpreted in line mode and in raw mode at line boundaries, but not in raw mode with chunks.
+//
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock
+
+// Import go standard library packages and tserr
+import (
+	"context" // context
+	"regexp"  // regexp
+	"strconv" // strconv
+	"strings" // strings
+	"time"    // time
+
+	"github.com/thorstenrie/tserr" // tserr
+)
+
+// directive is a directive in the input of the mocked Stdin with its name and argument.
+type directive struct {
+	name string // Name of the directive, e.g., delay
+	arg  string // Argument of the directive, e.g., 2s
+}
+
+// script holds the state of the directives during a run of the mocked Stdin.
+type script struct {
+	delay   time.Duration // Delay set by a directive
+	delayed bool          // True if the delay is set by a directive
+	hidden  bool          // True if the next line is not echoed
+	muted   bool          // True if the echo of the pseudo-terminal is switched off for a hidden line
+	eof     bool          // True if a directive ended the input
+	pos     int           // Position in the captured output after the last match
+}
+
+// Directives enables the interpretation of directives in the input of the mocked Stdin, if d is true. Lines starting with #!
+// are interpreted as directives instead of being written into Stdin. Directives returns an error if the mocked Stdin is executing.
+func (stdin *MockStdin) Directives(d bool) error {
+	// Return an error if mocked Stdin is executing
+	if e := stdin.notRunning(); e != nil {
+		return e
+	}
+	// Set the interpretation of directives to d
+	stdin.dir.Set(d)
+	// Return nil
+	return nil
+}
+
+// Output sets the captured output awaited by the directive wait-output to out. Output returns an error if the mocked Stdin is executing.
+func (stdin *MockStdin) Output(out *MockOutput) error {
+	// Return an error if mocked Stdin is executing
+	if e := stdin.notRunning(); e != nil {
+		return e
+	}
+	// Set the captured output to out
+	stdin.out.Set(out)
+	// Return nil
+	return nil
+}
+
+// WithDirectives returns an option to enable the interpretation of directives in the input of the mocked Stdin, if d is true. See Directives.
+func WithDirectives(d bool) Option {
+	return func(stdin *MockStdin) error {
+		return stdin.Directives(d)
+	}
+}
+
+// WithOutput returns an option to set the captured output awaited by the directive wait-output to out. See Output.
+func WithOutput(out *MockOutput) Option {
+	return func(stdin *MockStdin) error {
+		return stdin.Output(out)
+	}
+}
+
+// directives returns true, if directives are interpreted in the current input mode.
+func (stdin *MockStdin) directives() bool {
+	// Return false in raw mode with chunks
+	if stdin.raw.Get() && (stdin.chunk.Get() > 0) {
+		return false
+	}
+	// Return true, if directives are enabled
+	return stdin.dir.Get()
+}
+
+// parseDirective returns the directive in line i and true, if i is a directive. Otherwise, it returns false.
+func parseDirective(i []byte) (directive, bool) {
+	// Return false, if i does not start with #!
+	s, ok := strings.CutPrefix(strings.TrimRight(string(i), "\r\n"), "#!")
+	if !ok {
+		return directive{}, false
+	}
+	// Split the directive into its name and argument
+	name, arg, _ := strings.Cut(strings.TrimSpace(s), " ")
+	// Return the directive
+	return directive{name: name, arg: strings.TrimSpace(arg)}, true
+}
+
+// direct executes the directive d and updates the state of the directives sc. It returns an error, if the directive is
+// unknown or fails. It returns the error of the context, if the context is canceled while waiting for output.
+func (stdin *MockStdin) direct(ctx context.Context, sc *script, d directive) error {
+	switch d.name {
+	// Set the delay for the following lines
+	case "delay":
+		t, e := time.ParseDuration(d.arg)
+		// Return an error if parsing the delay fails
+		if e != nil {
+			return tserr.Op(&tserr.OpArgs{Op: "parse delay", Fn: d.arg, Err: e})
+		}
+		// Return an error if the delay is negative
+		if t < 0 {
+			return tserr.Higher(&tserr.HigherArgs{Var: "delay", Actual: int64(t), LowerBound: 0})
+		}
+		sc.delay, sc.delayed = t, true
+	// Do not echo the next line
+	case "hidden":
+		sc.hidden = true
+	// End the input
+	case "eof":
+		sc.eof = true
+	// Send the signal
+	case "signal":
+		sig, e := lookupSignal(d.arg)
+		// Return an error if the signal is not supported
+		if e != nil {
+			return e
+		}
+		return stdin.signal(sig)
+	// Wait for the output
+	case "wait-output":
+		return stdin.waitOutput(ctx, sc, d.arg)
+	// Return an error if the directive is unknown
+	default:
+		return tserr.NotExistent("directive " + d.name)
+	}
+	// Return nil
+	return nil
+}
+
+// waitOutput waits until the output set with Output contains the text t after the last match. The text t may be quoted.
+// It returns an error, if the output is not set or the text cannot be unquoted. It returns the error of the context, if
+// the context is canceled before.
+func (stdin *MockStdin) waitOutput(ctx context.Context, sc *script, t string) error {
+	// Unquote t, if quoted
+	if strings.HasPrefix(t, `"`) {
+		u, e := strconv.Unquote(t)
+		// Return an error if unquoting fails
+		if e != nil {
+			return tserr.Op(&tserr.OpArgs{Op: "unquote", Fn: t, Err: e})
+		}
+		t = u
+	}
+	// Retrieve the captured output
+	out := stdin.out.Get()
+	// Return an error if the captured output is not set
+	if out == nil {
+		return tserr.NotSet("Output")
+	}
+	// Wait for t in the captured output after the last match
+	pos, e := out.match(ctx, regexp.MustCompile(regexp.QuoteMeta(t)), sc.pos)
+	// Return an error, if waiting fails
+	if e != nil {
+		return e
+	}
+	// Store the position after the match
+	sc.pos = pos
+	// Return nil
+	return nil
+}
+
+// mute switches the echo of the pseudo-terminal off for a hidden line and on again for the next line, which is not hidden.
+func (stdin *MockStdin) mute(sc *script) {
+	// Return if no pseudo-terminal is used or the echo does not need to be switched
+	if !stdin.tty || (sc.hidden == sc.muted) {
+		return
+	}
+	// Switch the echo off for a hidden line and restore the visibility otherwise
+	if e := setEcho(stdin.r, stdin.v.Get() && !sc.hidden); e != nil {
+		stdin.fail(e)
+	}
+	// Store whether the echo is switched off
+	sc.muted = sc.hidden
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock_test
+
+// Import go standard library packages as well as tserr and tsmock
+import (
+	"bytes"     // bytes
+	"context"   // context
+	"errors"    // errors
+	"fmt"       // fmt
+	"io"        // io
+	"os"        // os
+	"os/signal" // os/signal
+	"runtime"   // runtime
+	"testing"   // testing
+	"time"      // time
+
+	"github.com/thorstenrie/tserr"  // tserr
+	"github.com/thorstenrie/tsmock" // tsmock
+)
+
+// TestDirectives tests the directives delay, hidden and eof. The test fails if the input does not omit the directives and the
+// input after eof, if the echo does not omit the hidden line or if the result does not report the written lines.
+func TestDirectives(t *testing.T) {
+	// Buffer for the echo
+	var echo bytes.Buffer
+	// Run a new mocked Stdin with directives and visible input
+	stdin := testDirectives("#! delay 1ms\nAragorn\n#! hidden\nsecret\nGandalf\n#! eof\nBoromir\n", t, tsmock.WithVisibility(true), tsmock.WithEcho(&echo))
+	// Read all input
+	b, e := io.ReadAll(os.Stdin)
+	// The test fails if ReadAll returns an error
+	if e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "ReadAll", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if the input does not omit the directives and the input after eof
+	if string(b) != "Aragorn\nsecret\nGandalf\n" {
+		t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "input", Actual: string(b), Want: "Aragorn\nsecret\nGandalf\n"}))
+	}
+	// Restore Stdin. The test fails if Restore returns an error.
+	if e := stdin.Restore(); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Restore", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if the echo does not omit the hidden line
+	if echo.String() != "Aragorn\nGandalf\n" {
+		t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "echo", Actual: echo.String(), Want: "Aragorn\nGandalf\n"}))
+	}
+	// The test fails if the result does not report three written lines
+	if res := stdin.Result(); !res.Finished || (res.Lines != 3) {
+		t.Error(tserr.Equal(&tserr.EqualArgs{Var: "Lines", Actual: int64(res.Lines), Want: 3}))
+	}
+}
+
+// TestDirectiveWaitOutput tests the directive wait-output. The test fails if the line after the directive is written
+// before the output contains the awaited text or if it is not written afterwards.
+func TestDirectiveWaitOutput(t *testing.T) {
+	// Retrieve a new mocked Stdout
+	out := tsmock.NewStdout()
+	// The test fails if Set returns an error
+	if e := out.Set(); e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "Set", Fn: "Stdout", Err: e}))
+	}
+	// Defer restoring os.Stdout
+	defer out.Restore()
+	// The test fails if Run returns an error
+	if e := out.Run(context.Background()); e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdout", Err: e}))
+	}
+	// Run a new mocked Stdin awaiting the prompt in the output
+	stdin := testDirectives("#! wait-output \"Password:\"\nsecret\n", t, tsmock.WithOutput(out))
+	// Defer restoring Stdin
+	defer stdin.Restore()
+	// Wait for the run to be able to write
+	time.Sleep(50 * time.Millisecond)
+	// The test fails if the line has been written before the prompt
+	if s := stdin.State(); s != tsmock.Running {
+		t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "State", Actual: s.String(), Want: tsmock.Running.String()}))
+	}
+	// Print the prompt
+	fmt.Print("Password:")
+	// Read the line. The test fails if ReadAll returns an error.
+	b, e := io.ReadAll(os.Stdin)
+	if e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "ReadAll", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if the line does not equal the input after the directive
+	if string(b) != "secret\n" {
+		t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "input", Actual: string(b), Want: "secret\n"}))
+	}
+}
+
+// TestDirectiveSignal tests the directive signal. The test fails if the signal is not received.
+func TestDirectiveSignal(t *testing.T) {
+	// Skip the test on Windows, which does not support sending signals to the process
+	if runtime.GOOS == "windows" {
+		t.Skip("sending signals is not supported on windows")
+	}
+	// Channel receiving the signal
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt)
+	// Defer stopping the notification
+	defer signal.Stop(c)
+	// Run a new mocked Stdin sending the signal
+	stdin := testDirectives("#! signal INT\nAragorn\n", t)
+	// Defer restoring Stdin
+	defer stdin.Restore()
+	// The test fails if the signal is not received within a second
+	select {
+	case <-c:
+	case <-time.After(time.Second):
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "signal", Actual: "not received", Want: "received"}))
+	}
+}
+
+// TestDirectiveUnknown tests an unknown directive. The test fails if Wait does not return a *LineError with the line number of the directive.
+func TestDirectiveUnknown(t *testing.T) {
+	// Run a new mocked Stdin with an unknown directive in the second line
+	stdin := testDirectives("Aragorn\n#! unknown\nBoromir\n", t)
+	// Defer restoring Stdin
+	defer stdin.Restore()
+	// Read all input
+	io.ReadAll(os.Stdin)
+	// The test fails if Wait does not return a *LineError
+	var le *tsmock.LineError
+	if e := stdin.Wait(context.Background()); !errors.As(e, &le) {
+		t.Fatal(tserr.Return(&tserr.ReturnArgs{Op: "Wait", Actual: "no *LineError", Want: "*LineError"}))
+	}
+	// The test fails if the line number is not 2
+	if le.Line != 2 {
+		t.Error(tserr.Equal(&tserr.EqualArgs{Var: "Line", Actual: int64(le.Line), Want: 2}))
+	}
+}
+
+// TestDirectiveEOFKeepOpen tests that the directive eof ends the input, if the mocked Stdin is held open. The test fails if the
+// run does not finish within a second or if the input does not omit the input after eof.
+func TestDirectiveEOFKeepOpen(t *testing.T) {
+	// Run a new mocked Stdin held open with the directive eof
+	stdin := testDirectives("Aragorn\n#! eof\nBoromir\n", t, tsmock.WithKeepOpen(true))
+	// Defer restoring Stdin
+	defer stdin.Restore()
+	// Read all input. The test fails if ReadAll returns an error.
+	b, e := io.ReadAll(os.Stdin)
+	if e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "ReadAll", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if the input does not omit the input after eof
+	if string(b) != "Aragorn\n" {
+		t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "input", Actual: string(b), Want: "Aragorn\n"}))
+	}
+	// Retrieve a context with a timeout
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	// Defer cancel function
+	defer cancel()
+	// The test fails if the run does not finish within a second
+	if e := stdin.Wait(ctx); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Wait", Fn: "Stdin", Err: e}))
+	}
+}
+
+// testDirectives returns a new running mocked Stdin with directives enabled, visibility set to false, the input in and the options opts.
+// The test fails if retrieving or running the mocked Stdin fails.
+func testDirectives(in string, t *testing.T, opts ...tsmock.Option) *tsmock.MockStdin {
+	// Panic if t is nil
+	if t == nil {
+		panic(tserr.NilPtr())
+	}
+	// Retrieve a new mocked Stdin with directives enabled
+	stdin, e := tsmock.NewStdin(append([]tsmock.Option{tsmock.WithVisibility(false), tsmock.WithDirectives(true), tsmock.WithString(in)}, opts...)...)
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Return the running mocked Stdin
+	return stdin
+}
@@ -0,0 +1,182 @@
+// Echo.go provides the styling of the echo of visible input of the mocked Stdin. The echo can be masked with a star per
+// character or redacted completely, so that secret input shows up safely in logs. It can be marked with a prefix and a
+// color. The echo is styled line by line, regardless of how the input is written or echoed by a pseudo-terminal. Line endings
+// \r\n of a pseudo-terminal are normalized to \n. The echo is written to the writer set with Echo, for example a writer returned
+// by LogWriter.
+//
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock
+
+// Import go standard library packages
+import (
+	"bytes"        // bytes
+	"fmt"          // fmt
+	"strings"      // strings
+	"unicode/utf8" // unicode/utf8
+)
+
+// Mask is the masking mode of the echo of the mocked Stdin.
+type Mask int
+
+const (
+	MaskNone     Mask = iota // The echo is not masked
+	MaskStars                // Each character of the echo is replaced by a star
+	MaskRedacted             // The echo of each line is replaced by [redacted]
+)
+
+// Color is the color of the echo of the mocked Stdin given as parameter of an ANSI escape sequence, e.g., 32 for green.
+type Color string
+
+const (
+	NoColor Color = ""   // The echo is not colored
+	Red     Color = "31" // Red
+	Green   Color = "32" // Green
+	Yellow  Color = "33" // Yellow
+	Blue    Color = "34" // Blue
+	Magenta Color = "35" // Magenta
+	Cyan    Color = "36" // Cyan
+	Gray    Color = "90" // Gray
+)
+
+// redacted replaces the echo of a line in masking mode MaskRedacted.
+const redacted = "[redacted]"
+
+// EchoMask sets the masking mode of the echo of visible input to m. With MaskStars, each character is replaced by a star. With MaskRedacted,
+// each line is replaced by [redacted]. Line endings are preserved. EchoMask returns an error if the mocked Stdin is executing.
+func (stdin *MockStdin) EchoMask(m Mask) error {
+	// Return an error if mocked Stdin is executing
+	if e := stdin.notRunning(); e != nil {
+		return e
+	}
+	// Set the masking mode to m
+	stdin.mask.Set(m)
+	// Return nil
+	return nil
+}
+
+// EchoPrefix sets the prefix written before each echoed line or chunk of visible input to p. EchoPrefix returns an error if the mocked Stdin is executing.
+func (stdin *MockStdin) EchoPrefix(p string) error {
+	// Return an error if mocked Stdin is executing
+	if e := stdin.notRunning(); e != nil {
+		return e
+	}
+	// Set the prefix to p
+	stdin.prefix.Set(p)
+	// Return nil
+	return nil
+}
+
+// EchoColor sets the color of the echo of visible input to c. The echo is colored with ANSI escape sequences. If c is NoColor, the echo is
+// not colored, which is the default. EchoColor returns an error if the mocked Stdin is executing.
+func (stdin *MockStdin) EchoColor(c Color) error {
+	// Return an error if mocked Stdin is executing
+	if e := stdin.notRunning(); e != nil {
+		return e
+	}
+	// Set the color to c
+	stdin.color.Set(c)
+	// Return nil
+	return nil
+}
+
+// WithEchoMask returns an option to set the masking mode of the echo of visible input to m. See EchoMask.
+func WithEchoMask(m Mask) Option {
+	return func(stdin *MockStdin) error {
+		return stdin.EchoMask(m)
+	}
+}
+
+// WithEchoPrefix returns an option to set the prefix of the echo of visible input to p. See EchoPrefix.
+func WithEchoPrefix(p string) Option {
+	return func(stdin *MockStdin) error {
+		return stdin.EchoPrefix(p)
+	}
+}
+
+// WithEchoColor returns an option to set the color of the echo of visible input to c. See EchoColor.
+func WithEchoColor(c Color) Option {
+	return func(stdin *MockStdin) error {
+		return stdin.EchoColor(c)
+	}
+}
+
+// print adds the echo of visible input i and writes each completed line styled as configured to the echo writer, or os.Stdout if the
+// echo writer is nil. An incomplete line is kept until it is completed or written by flushEcho. Line endings \r\n of a pseudo-terminal
+// are normalized to \n.
+func (stdin *MockStdin) print(i string) {
+	// Lock the mutex
+	stdin.emu.Lock()
+	// Defer unlocking the mutex
+	defer stdin.emu.Unlock()
+	// Add i to the incomplete line
+	stdin.ebuf = append(stdin.ebuf, i...)
+	// Write each completed line
+	for {
+		// Retrieve the end of the next line
+		k := bytes.IndexByte(stdin.ebuf, '\n')
+		// Return if no line is completed
+		if k < 0 {
+			return
+		}
+		// Retrieve the line and normalize the line ending of a pseudo-terminal
+		l := string(stdin.ebuf[:k+1])
+		if stdin.tty && strings.HasSuffix(l, "\r\n") {
+			l = l[:len(l)-2] + "\n"
+		}
+		// Write the styled line
+		stdin.emit(stdin.style(l))
+		// Remove the line
+		stdin.ebuf = stdin.ebuf[k+1:]
+	}
+}
+
+// flushEcho writes an incomplete last line of the echo styled as configured, if any.
+func (stdin *MockStdin) flushEcho() {
+	// Lock the mutex
+	stdin.emu.Lock()
+	// Defer unlocking the mutex
+	defer stdin.emu.Unlock()
+	// Write the styled incomplete line, if any
+	if len(stdin.ebuf) > 0 {
+		stdin.emit(stdin.style(string(stdin.ebuf)))
+	}
+	// Reset the incomplete line
+	stdin.ebuf = nil
+}
+
+// emit writes the styled echo i to the echo writer, or os.Stdout if the echo writer is nil.
+func (stdin *MockStdin) emit(i string) {
+	// Retrieve the echo writer
+	w := stdin.echo.Get()
+	// Print i to os.Stdout, if the echo writer is nil
+	if w == nil {
+		fmt.Print(i)
+		return
+	}
+	// Print i to the echo writer
+	fmt.Fprint(w, i)
+}
+
+// style returns the echo i masked, colored and prefixed as configured. Line endings at the end of i are preserved.
+func (stdin *MockStdin) style(i string) string {
+	// Split i into text and line ending
+	t := strings.TrimRight(i, "\r\n")
+	end := i[len(t):]
+	// Mask the text
+	switch stdin.mask.Get() {
+	// Replace each character with a star
+	case MaskStars:
+		t = strings.Repeat("*", utf8.RuneCountInString(t))
+	// Replace the text with [redacted]
+	case MaskRedacted:
+		t = redacted
+	}
+	// Color the text, if a color is set
+	if c := stdin.color.Get(); (c != NoColor) && (t != "") {
+		t = "\x1b[" + string(c) + "m" + t + "\x1b[0m"
+	}
+	// Return the prefixed text with the line ending
+	return stdin.prefix.Get() + t + end
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock_test
+
+// Import go standard library packages as well as tserr and tsmock
+import (
+	"bytes"   // bytes
+	"context" // context
+	"fmt"     // fmt
+	"io"      // io
+	"os"      // os
+	"testing" // testing
+
+	"github.com/thorstenrie/tserr"  // tserr
+	"github.com/thorstenrie/tsmock" // tsmock
+)
+
+// logT is a test, which records the logged lines.
+type logT struct {
+	testing.TB          // Embedded test
+	lines      []string // Logged lines
+}
+
+// Log records the logged line.
+func (l *logT) Log(args ...any) {
+	l.lines = append(l.lines, fmt.Sprint(args...))
+}
+
+// TestEchoMask tests the masking modes of the echo. The test fails if the echo is not masked.
+func TestEchoMask(t *testing.T) {
+	// The test fails if the echo is not replaced by stars per character
+	testEcho("*******\n**\n", t, tsmock.WithEchoMask(tsmock.MaskStars))
+	// The test fails if the echo is not redacted
+	testEcho("[redacted]\n[redacted]\n", t, tsmock.WithEchoMask(tsmock.MaskRedacted))
+}
+
+// TestEchoStyle tests the prefix and the color of the echo. The test fails if the echo is not prefixed and colored.
+func TestEchoStyle(t *testing.T) {
+	// The test fails if the echo is not prefixed and colored
+	testEcho("> \x1b[32mAragorn\x1b[0m\n> \x1b[32mGé\x1b[0m\n", t, tsmock.WithEchoPrefix("> "), tsmock.WithEchoColor(tsmock.Green))
+}
+
+// TestEchoLines tests that the echo is styled line by line, if the input is typed character by character or written in chunks
+// of several lines. The test fails if the echo is not prefixed or masked once per line.
+func TestEchoLines(t *testing.T) {
+	// Retrieve a typing model with a short delay
+	m, e := tsmock.NewConstantTyping(10000)
+	// The test fails if NewConstantTyping returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewConstantTyping", Fn: "10000", Err: e}))
+	}
+	// The test fails if the typed echo is not prefixed once per line
+	testEcho("> Aragorn\n> Gé\n", t, tsmock.WithTyping(m), tsmock.WithEchoPrefix("> "))
+	// The test fails if the typed echo is not redacted once per line
+	testEcho("[redacted]\n[redacted]\n", t, tsmock.WithTyping(m), tsmock.WithEchoMask(tsmock.MaskRedacted))
+	// The test fails if the echo of a chunk with two lines is not prefixed once per line
+	testEcho("> Aragorn\n> Gé\n", t, tsmock.WithRaw(true), tsmock.WithChunk(64), tsmock.WithEchoPrefix("> "))
+}
+
+// TestLogWriter tests LogWriter to log each line and an incomplete last line on cleanup. The test fails if the logged lines
+// do not equal the written lines.
+func TestLogWriter(t *testing.T) {
+	// Test recording the logged lines
+	l := &logT{TB: t}
+	// Run the writer in a sub test to trigger its cleanup
+	t.Run("LogWriter", func(s *testing.T) {
+		l.TB = s
+		// Retrieve a new log writer
+		w := tsmock.LogWriter(l)
+		// Write two complete lines and an incomplete line
+		io.WriteString(w, "Aragorn\r\nGan")
+		io.WriteString(w, "dalf\nGimli")
+	})
+	// Expected logged lines
+	want := []string{"Aragorn", "Gandalf", "Gimli"}
+	// The test fails if the number of logged lines does not equal the expected number
+	if len(l.lines) != len(want) {
+		t.Fatal(tserr.Equal(&tserr.EqualArgs{Var: "lines", Actual: int64(len(l.lines)), Want: int64(len(want))}))
+	}
+	// The test fails if a logged line does not equal the expected line
+	for i := range want {
+		if l.lines[i] != want[i] {
+			t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "line", Actual: l.lines[i], Want: want[i]}))
+		}
+	}
+}
+
+// testEcho runs a new mocked Stdin with the options opts, visibility set to true and two lines. The test fails if the echo
+// does not equal want or if any error occurs.
+func testEcho(want string, t *testing.T, opts ...tsmock.Option) {
+	// Panic if t is nil
+	if t == nil {
+		panic(tserr.NilPtr())
+	}
+	// Buffer for the echo
+	var echo bytes.Buffer
+	// Retrieve a new mocked Stdin with two lines and the options
+	stdin, e := tsmock.NewStdin(append(opts, tsmock.WithEcho(&echo), tsmock.WithString("Aragorn\nGé\n"))...)
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Read all input
+	io.ReadAll(os.Stdin)
+	// Restore Stdin. The test fails if Restore returns an error.
+	if e := stdin.Restore(); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Restore", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if the echo does not equal want
+	if echo.String() != want {
+		t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "echo", Actual: echo.String(), Want: want}))
+	}
+}
@@ -0,0 +1,49 @@
+// Errors.go provides the error history of the mocked Stdin. All errors occurring since the input has been set are kept and
+// returned joined by Err. Errors occurring while writing the input are wrapped in a *LineError, which holds the number
+// of the line, the byte offset and the text being written.
+//
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock
+
+// Import go standard library packages errors and fmt
+import (
+	"errors" // errors
+	"fmt"    // fmt
+)
+
+// ErrUnconsumed is returned, if input of the mocked Stdin has never been consumed by the program.
+var ErrUnconsumed = errors.New("input never consumed")
+
+// LineError is an error occurring while writing a line of the input of the mocked Stdin. It holds the number of the line,
+// the byte offset of the text in the input, the text being written and the underlying error. In raw mode with chunks,
+// the text is the chunk being written and the line is the line in which the chunk starts.
+type LineError struct {
+	Line   int    // Number of the line, starting with 1
+	Offset int64  // Byte offset of the text in the input
+	Text   string // Text being written
+	Err    error  // Underlying error
+}
+
+// Error returns the error message with the line context.
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d at offset %d %q: %v", e.Line, e.Offset, e.Text, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// fail adds the error e to the errors of the mocked Stdin. Nil errors are ignored.
+func (stdin *MockStdin) fail(e error) {
+	// Return if e is nil
+	if e == nil {
+		return
+	}
+	// Add e to the errors
+	stdin.errs.Update(func(errs []error) ([]error, error) {
+		return append(errs, e), nil
+	})
+}
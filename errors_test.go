@@ -0,0 +1,103 @@
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock_test
+
+// Import go standard library packages as well as tserr and tsmock
+import (
+	"context" // context
+	"errors"  // errors
+	"io"      // io
+	"os"      // os
+	"strings" // strings
+	"syscall" // syscall
+	"testing" // testing
+	"time"    // time
+
+	"github.com/thorstenrie/tserr"  // tserr
+	"github.com/thorstenrie/tsmock" // tsmock
+)
+
+// TestLineErrorBrokenPipe tests that a broken pipe while writing the second line is reported as *LineError with the
+// number, the byte offset and the text of the second line. The test fails if Err does not contain the expected *LineError.
+func TestLineErrorBrokenPipe(t *testing.T) {
+	// Retrieve a new fake clock
+	c := tsmock.NewFakeClock(time.Time{})
+	// Retrieve a new mocked Stdin with a delay and the fake clock
+	stdin, e := tsmock.NewStdin(tsmock.WithClock(c), tsmock.WithDelay(time.Second), tsmock.WithVisibility(false), tsmock.WithString(contents))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Wait for the first line to be written
+	if e := c.BlockUntil(context.Background(), 1); e != nil {
+		t.Fatal(e)
+	}
+	// Read the first line
+	io.ReadFull(os.Stdin, make([]byte, len("Aragorn\n")))
+	// Close the read end of the pipe to break the pipe
+	os.Stdin.Close()
+	// Advance the fake clock to write the second line
+	c.Advance(time.Second)
+	// Wait for the run to finish
+	stdin.Wait(context.Background())
+	// Retrieve the errors of the mocked Stdin
+	err := stdin.Err()
+	// Restore Stdin
+	stdin.Restore()
+	// The test fails if the errors do not contain a *LineError
+	var le *tsmock.LineError
+	if !errors.As(err, &le) {
+		t.Fatal(tserr.Return(&tserr.ReturnArgs{Op: "Err", Actual: "no *LineError", Want: "*LineError"}))
+	}
+	// The test fails if the line number is not 2
+	if le.Line != 2 {
+		t.Error(tserr.Equal(&tserr.EqualArgs{Var: "Line", Actual: int64(le.Line), Want: 2}))
+	}
+	// The test fails if the offset does not equal the length of the first line
+	if le.Offset != int64(len("Aragorn\n")) {
+		t.Error(tserr.Equal(&tserr.EqualArgs{Var: "Offset", Actual: le.Offset, Want: int64(len("Aragorn\n"))}))
+	}
+	// The test fails if the text does not equal the second line
+	if le.Text != "Boromir\n" {
+		t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "Text", Want: "Boromir\n", Actual: le.Text}))
+	}
+	// The test fails if the underlying error is not a broken pipe
+	if !errors.Is(err, syscall.EPIPE) {
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "Err", Actual: "no EPIPE", Want: "EPIPE"}))
+	}
+}
+
+// TestLineErrorTooLong tests that a line exceeding the maximum token size is reported as *LineError with the number
+// of the line. The test fails if Err does not contain the expected *LineError.
+func TestLineErrorTooLong(t *testing.T) {
+	// Retrieve a new mocked Stdin with a line exceeding the maximum token size as second line
+	stdin, e := tsmock.NewStdin(tsmock.WithVisibility(false), tsmock.WithString("Aragorn\n"+strings.Repeat("x", 100*1024)))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Read all input
+	io.ReadAll(os.Stdin)
+	// Restore Stdin and retrieve the errors
+	err := stdin.Restore()
+	// The test fails if the errors do not contain a *LineError for the second line
+	var le *tsmock.LineError
+	if !errors.As(err, &le) {
+		t.Fatal(tserr.Return(&tserr.ReturnArgs{Op: "Restore", Actual: "no *LineError", Want: "*LineError"}))
+	}
+	// The test fails if the line number is not 2
+	if le.Line != 2 {
+		t.Error(tserr.Equal(&tserr.EqualArgs{Var: "Line", Actual: int64(le.Line), Want: 2}))
+	}
+}
@@ -0,0 +1,162 @@
+// Expect.go provides an expect-style interaction with the program under test. The Expecter watches the captured
+// output of os.Stdout and sends replies to the mocked Stdin only after the expected output appeared.
+//
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock
+
+// Import go standard library packages and tserr
+import (
+	"context" // context
+	"errors"  // errors
+	"fmt"     // fmt
+	"os"      // os
+	"regexp"  // regexp
+	"time"    // time
+
+	"github.com/thorstenrie/tserr" // tserr
+)
+
+// DefaultExpectTimeout is the default timeout of Expect.
+const DefaultExpectTimeout = 5 * time.Second
+
+// Expecter contains the internal state of an expect-style interaction. It holds the mocked Stdin receiving the replies,
+// a mocked Stdout, a timeout and the position in the captured output after the last match.
+type Expecter struct {
+	stdin   *MockStdin                  // Mocked Stdin
+	out     *MockStdout                 // Mocked Stdout
+	echo    bool                        // True if the echo of the mocked Stdin is routed to the original os.Stdout
+	timeout SafeVariable[time.Duration] // Timeout of Expect
+	pos     SafeVariable[int]           // Position in the captured output after the last match
+}
+
+// ExpectError is returned, if an expectation timed out. It holds the expected pattern, the timeout and
+// the output received since the last match.
+type ExpectError struct {
+	Pattern string        // Expected pattern
+	Timeout time.Duration // Timeout of the expectation
+	Output  string        // Output received since the last match
+}
+
+// Error returns the error message of the expectation which timed out.
+func (e *ExpectError) Error() string {
+	return fmt.Sprintf("expectation %q timed out after %v, received output: %q", e.Pattern, e.Timeout, e.Output)
+}
+
+// NewExpecter returns a new Expecter on stdin. It captures os.Stdout, sets the input of stdin to the replies sent with
+// Send and runs stdin with the context ctx. If stdin echoes to os.Stdout, the echo of the replies is written to the original
+// os.Stdout instead of the captured output, so that Expect does not match the replies. The Expecter must be closed with Close
+// to restore os.Stdin and os.Stdout. It returns an error, if the capture, setting the input or running stdin fails.
+func NewExpecter(ctx context.Context, stdin *MockStdin) (*Expecter, error) {
+	// Return an error if stdin is nil
+	if stdin == nil {
+		return nil, tserr.NilPtr()
+	}
+	// Retrieve a new Expecter on stdin
+	x := &Expecter{stdin: stdin, out: NewStdout()}
+	// Set the timeout to the default timeout
+	x.timeout.Set(DefaultExpectTimeout)
+	// Set the input of stdin to the replies
+	if e := stdin.SetInteractive(); e != nil {
+		return nil, e
+	}
+	// Route the echo of stdin to the original os.Stdout, if stdin echoes to os.Stdout
+	if stdin.echo.Get() == nil {
+		stdin.echo.Set(os.Stdout)
+		x.echo = true
+	}
+	// Capture os.Stdout
+	if e := x.out.Set(); e != nil {
+		x.restore()
+		return nil, e
+	}
+	// Run the mocked Stdout and stdin
+	if e := errors.Join(x.out.Run(context.Background()), stdin.Run(ctx)); e != nil {
+		x.restore()
+		return nil, e
+	}
+	// Return the Expecter
+	return x, nil
+}
+
+// Timeout sets the timeout of Expect to d. It returns an error if d is lower or equal to zero.
+func (x *Expecter) Timeout(d time.Duration) error {
+	// Return an error if d is not positive
+	if d <= 0 {
+		return tserr.Higher(&tserr.HigherArgs{Var: "d", Actual: int64(d), LowerBound: 1})
+	}
+	// Set timeout to d
+	x.timeout.Set(d)
+	// Return nil
+	return nil
+}
+
+// Expect waits until re matches the output of os.Stdout received since the last match. It returns an *ExpectError
+// if re does not match within the timeout set with Timeout.
+func (x *Expecter) Expect(re *regexp.Regexp) error {
+	// Expect re with the timeout
+	return x.ExpectTimeout(re, x.timeout.Get())
+}
+
+// ExpectTimeout waits until re matches the output of os.Stdout received since the last match. It returns an *ExpectError
+// if re does not match within d.
+func (x *Expecter) ExpectTimeout(re *regexp.Regexp, d time.Duration) error {
+	// Return an error if re is nil
+	if re == nil {
+		return tserr.NilPtr()
+	}
+	// Retrieve a context with timeout d
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	// Defer cancel function
+	defer cancel()
+	// Retrieve the position after the last match
+	from := x.pos.Get()
+	// Wait until re matches the output
+	to, e := x.out.match(ctx, re, from)
+	// Return an ExpectError if re does not match within d
+	if e != nil {
+		return &ExpectError{Pattern: re.String(), Timeout: d, Output: string(x.out.Bytes()[from:])}
+	}
+	// Set the position after the match
+	x.pos.Set(to)
+	// Return nil
+	return nil
+}
+
+// Send sends line followed by a newline to the mocked Stdin. The line is processed with the delay and visibility
+// of the mocked Stdin. It returns an error, if the Expecter is closed or its context is canceled.
+func (x *Expecter) Send(line string) error {
+	// Send line followed by a newline to the mocked Stdin
+	return x.stdin.SendLine(line)
+}
+
+// Output returns the output of os.Stdout captured so far.
+func (x *Expecter) Output() string {
+	// Return the captured output
+	return x.out.String()
+}
+
+// Close ends the input of the mocked Stdin, waits until all replies have been processed and restores os.Stdin and os.Stdout.
+// It returns the errors of the mocked Stdin and the capture, if any.
+func (x *Expecter) Close() error {
+	// End the input of the mocked Stdin, unless it has already been ended or canceled
+	x.stdin.SendEOF()
+	// Wait until all replies have been processed
+	<-x.stdin.Done()
+	// Restore os.Stdin and os.Stdout and return the errors, if any
+	return x.restore()
+}
+
+// restore restores os.Stdin and os.Stdout and resets the echo of the mocked Stdin to os.Stdout, if routed to the original os.Stdout.
+// It returns the errors of the mocked Stdin and the capture, if any.
+func (x *Expecter) restore() error {
+	// Restore os.Stdin and os.Stdout
+	e := errors.Join(x.stdin.Restore(), x.out.Restore())
+	// Reset the echo of the mocked Stdin to os.Stdout, if routed to the original os.Stdout
+	if x.echo {
+		x.stdin.echo.Set(nil)
+	}
+	// Return the errors, if any
+	return e
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock_test
+
+// Import go standard library packages as well as tserr and tsmock
+import (
+	"bufio"   // bufio
+	"context" // context
+	"errors"  // errors
+	"fmt"     // fmt
+	"os"      // os
+	"regexp"  // regexp
+	"testing" // testing
+	"time"    // time
+
+	"github.com/thorstenrie/tserr"  // tserr
+	"github.com/thorstenrie/tsmock" // tsmock
+)
+
+// TestExpect tests an expect-style interaction with a program prompting for names. Each name is sent only after
+// the prompt appeared. The test fails if an expectation times out or if any other error occurs.
+func TestExpect(t *testing.T) {
+	// Retrieve a new mocked Stdin with visibility set to false
+	stdin, e := tsmock.NewStdin(tsmock.WithVisibility(false))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Retrieve a new Expecter on stdin
+	x, e := tsmock.NewExpecter(context.Background(), stdin)
+	// The test fails if NewExpecter returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewExpecter", Fn: "Stdin", Err: e}))
+	}
+	// Start the program under test
+	done := make(chan struct{})
+	go testExpectProgram(2, done)
+	// Answer the prompts of the program
+	for _, name := range []string{"Aragorn", "Gimli"} {
+		// The test fails if the prompt does not appear
+		if e := x.Expect(regexp.MustCompile(`Name: $`)); e != nil {
+			t.Error(e)
+		}
+		// The test fails if Send returns an error
+		if e := x.Send(name); e != nil {
+			t.Error(e)
+		}
+		// The test fails if the greeting does not appear
+		if e := x.Expect(regexp.MustCompile("Hello " + name + "\n")); e != nil {
+			t.Error(e)
+		}
+	}
+	// Wait for the program under test to exit
+	<-done
+	// The test fails if Close returns an error
+	if e := x.Close(); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Close", Fn: "Expecter", Err: e}))
+	}
+}
+
+// TestExpectTimeout tests that an expectation which does not match times out. The test fails if ExpectTimeout
+// does not return an *ExpectError with the received output or if any other error occurs.
+func TestExpectTimeout(t *testing.T) {
+	// Retrieve a new mocked Stdin with visibility set to false
+	stdin, e := tsmock.NewStdin(tsmock.WithVisibility(false))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Retrieve a new Expecter on stdin
+	x, e := tsmock.NewExpecter(context.Background(), stdin)
+	// The test fails if NewExpecter returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewExpecter", Fn: "Stdin", Err: e}))
+	}
+	// Print a prompt
+	fmt.Print("Password: ")
+	// Expect an output which never appears
+	e = x.ExpectTimeout(regexp.MustCompile("Name: "), 50*time.Millisecond)
+	// The test fails if ExpectTimeout does not return an ExpectError
+	var xe *tsmock.ExpectError
+	if !errors.As(e, &xe) {
+		t.Error(tserr.NilFailed("ExpectTimeout"))
+	} else if xe.Output != "Password: " {
+		// The test fails if the received output is not reported
+		t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "Output", Want: "Password: ", Actual: xe.Output}))
+	}
+	// The test fails if Close returns an error
+	if e := x.Close(); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Close", Fn: "Expecter", Err: e}))
+	}
+}
+
+// TestExpectEcho tests that the echo of a reply with default visibility is not part of the captured output. The test fails
+// if an expectation matches the reply, if the captured output contains the reply or if any other error occurs.
+func TestExpectEcho(t *testing.T) {
+	// Retrieve a new mocked Stdin with default visibility
+	stdin, e := tsmock.NewStdin()
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Retrieve a new Expecter on stdin
+	x, e := tsmock.NewExpecter(context.Background(), stdin)
+	// The test fails if NewExpecter returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewExpecter", Fn: "Stdin", Err: e}))
+	}
+	// Send a reply containing the pattern. The test fails if Send returns an error.
+	if e := x.Send("Name: Aragorn"); e != nil {
+		t.Error(e)
+	}
+	// Read the reply. The test fails if ReadString returns an error.
+	if _, e := bufio.NewReader(os.Stdin).ReadString('\n'); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "ReadString", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if the expectation matches the echo of the reply
+	var xe *tsmock.ExpectError
+	if e := x.ExpectTimeout(regexp.MustCompile("Name: "), 50*time.Millisecond); !errors.As(e, &xe) {
+		t.Error(tserr.NilFailed("ExpectTimeout"))
+	}
+	// The test fails if Close returns an error
+	if e := x.Close(); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Close", Fn: "Expecter", Err: e}))
+	}
+	// The test fails if the captured output contains the reply
+	if o := x.Output(); o != "" {
+		t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "Output", Actual: o, Want: ""}))
+	}
+}
+
+// testExpectProgram prompts n times for a name on os.Stdout, reads the name from os.Stdin and prints a greeting.
+// It closes done after it finished.
+func testExpectProgram(n int, done chan struct{}) {
+	// Close done after the program finished
+	defer close(done)
+	// Retrieve a new scanner on Stdin
+	s := bufio.NewScanner(os.Stdin)
+	for i := 0; i < n; i++ {
+		// Print the prompt
+		fmt.Print("Name: ")
+		// Return if no name is received
+		if !s.Scan() {
+			return
+		}
+		// Print the greeting
+		fmt.Printf("Hello %s\n", s.Text())
+	}
+}
@@ -0,0 +1,75 @@
+// Fd.go provides the installation of the mocked Stdin at file descriptor 0. By default, only the variable os.Stdin is
+// replaced. Code reading file descriptor 0 directly, e.g., with syscall.Read, os.NewFile or cgo libraries, still reads
+// the original Stdin. If enabled, the pipe or pseudo-terminal is also installed at file descriptor 0, which is restored
+// with Restore. Installing at file descriptor 0 is only supported on Linux.
+//
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock
+
+// Import tserr
+import (
+	"github.com/thorstenrie/tserr" // tserr
+)
+
+// Fd0 enables the installation of the mocked Stdin at file descriptor 0, if f is true. The pipe or pseudo-terminal is installed at
+// file descriptor 0 when the input is set and the previous file descriptor 0 is restored with Restore. Reads of file descriptor 0 are
+// not detected by Unconsumed, strict mode or lockstep mode as reads of os.Stdin. Fd0 returns an error if the kind is KindClosed, since
+// closing file descriptor 0 would let the next opened file take its place, or if the mocked Stdin is executing.
+func (stdin *MockStdin) Fd0(f bool) error {
+	// Return an error if the kind is KindClosed
+	if f && (stdin.kind.Get() == KindClosed) {
+		return errFd0Closed()
+	}
+	// Return an error if mocked Stdin is executing
+	if e := stdin.notRunning(); e != nil {
+		return e
+	}
+	// Set the installation at file descriptor 0 to f
+	stdin.fd0.Set(f)
+	// Return nil
+	return nil
+}
+
+// WithFd0 returns an option to enable the installation of the mocked Stdin at file descriptor 0, if f is true. See Fd0.
+func WithFd0(f bool) Option {
+	return func(stdin *MockStdin) error {
+		return stdin.Fd0(f)
+	}
+}
+
+// errFd0Closed returns an error for the installation of a closed file at file descriptor 0.
+func errFd0Closed() error {
+	return tserr.Forbidden("Fd0 with KindClosed")
+}
+
+// install installs the read end of the pipe at file descriptor 0, if enabled. It returns an error, if installing fails.
+func (stdin *MockStdin) install() error {
+	// Return nil, if installing at file descriptor 0 is disabled
+	if !stdin.fd0.Get() {
+		return nil
+	}
+	// Install the read end of the pipe at file descriptor 0
+	s, e := installFd0(stdin.r)
+	// Return an error if installing fails
+	if e != nil {
+		return e
+	}
+	// Store the saved file descriptor 0
+	stdin.sfd, stdin.ifd = s, true
+	// Return nil
+	return nil
+}
+
+// uninstall restores the saved file descriptor 0, if the read end of the pipe is installed at file descriptor 0.
+func (stdin *MockStdin) uninstall() {
+	// Return if the read end of the pipe is not installed at file descriptor 0
+	if !stdin.ifd {
+		return
+	}
+	// Restore the saved file descriptor 0 and add an error, if restoring fails
+	stdin.fail(restoreFd0(stdin.sfd))
+	// Reset the saved file descriptor 0
+	stdin.sfd, stdin.ifd = -1, false
+}
@@ -0,0 +1,94 @@
+//go:build linux
+
+// Fd_linux.go provides the installation of the mocked Stdin at file descriptor 0 on Linux. The read end of the pipe or
+// the pseudo-terminal is opened again in blocking mode and duplicated onto file descriptor 0. The previous file descriptor 0
+// is saved and restored with Restore.
+//
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock
+
+// Import go standard library packages and tserr
+import (
+	"os"      // os
+	"strconv" // strconv
+	"syscall" // syscall
+
+	"github.com/thorstenrie/tserr" // tserr
+)
+
+// installFd0 installs f at file descriptor 0. It returns a duplicate of the previous file descriptor 0, or -1 if file
+// descriptor 0 was closed. It returns an error, if installing f fails.
+func installFd0(f *os.File) (int, error) {
+	// Retrieve the file descriptor of f without changing its blocking mode
+	fd, e := fileFd(f)
+	// Return an error if retrieving the file descriptor fails
+	if e != nil {
+		return -1, tserr.Op(&tserr.OpArgs{Op: "get file descriptor", Fn: f.Name(), Err: e})
+	}
+	// Open a new file description of f in blocking mode, since file descriptor 0 is shared with other readers
+	n, e := syscall.Open("/proc/self/fd/"+strconv.Itoa(fd), syscall.O_RDONLY|syscall.O_NOCTTY|syscall.O_CLOEXEC, 0)
+	// Return an error if opening fails
+	if e != nil {
+		return -1, tserr.Op(&tserr.OpArgs{Op: "open", Fn: f.Name(), Err: e})
+	}
+	// Defer closing the new file description
+	defer syscall.Close(n)
+	// Save the previous file descriptor 0
+	saved, _, errno := syscall.Syscall(syscall.SYS_FCNTL, 0, syscall.F_DUPFD_CLOEXEC, 0)
+	// Return an error if saving fails, unless file descriptor 0 was closed
+	if (errno != 0) && (errno != syscall.EBADF) {
+		return -1, tserr.Op(&tserr.OpArgs{Op: "save", Fn: "file descriptor 0", Err: errno})
+	}
+	// Mark a closed file descriptor 0
+	s := int(saved)
+	if errno == syscall.EBADF {
+		s = -1
+	}
+	// Duplicate the new file description onto file descriptor 0
+	if e := syscall.Dup3(n, 0, 0); e != nil {
+		// Close the saved file descriptor
+		if s >= 0 {
+			syscall.Close(s)
+		}
+		return -1, tserr.Op(&tserr.OpArgs{Op: "install", Fn: "file descriptor 0", Err: e})
+	}
+	// Return the saved file descriptor
+	return s, nil
+}
+
+// restoreFd0 restores file descriptor 0 to the saved file descriptor s and closes s. If s is -1, file descriptor 0 is closed.
+// It returns an error, if restoring fails.
+func restoreFd0(s int) error {
+	// Close file descriptor 0, if it was closed before
+	if s < 0 {
+		syscall.Close(0)
+		return nil
+	}
+	// Defer closing the saved file descriptor
+	defer syscall.Close(s)
+	// Duplicate the saved file descriptor onto file descriptor 0
+	if e := syscall.Dup3(s, 0, 0); e != nil {
+		return tserr.Op(&tserr.OpArgs{Op: "restore", Fn: "file descriptor 0", Err: e})
+	}
+	// Return nil
+	return nil
+}
+
+// fileFd returns the file descriptor of f without changing its blocking mode.
+func fileFd(f *os.File) (int, error) {
+	// Retrieve the raw connection of f
+	c, e := f.SyscallConn()
+	// Return an error if retrieving the raw connection fails
+	if e != nil {
+		return -1, e
+	}
+	// Retrieve the file descriptor
+	fd := -1
+	if e := c.Control(func(u uintptr) { fd = int(u) }); e != nil {
+		return -1, e
+	}
+	// Return the file descriptor
+	return fd, nil
+}
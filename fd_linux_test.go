@@ -0,0 +1,62 @@
+//go:build linux
+
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock_test
+
+// Import go standard library packages as well as tserr and tsmock
+import (
+	"context" // context
+	"syscall" // syscall
+	"testing" // testing
+
+	"github.com/thorstenrie/tserr"  // tserr
+	"github.com/thorstenrie/tsmock" // tsmock
+)
+
+// TestFd0 tests the installation of the mocked Stdin at file descriptor 0. The test fails if the input read from file descriptor 0
+// does not equal the contents, if file descriptor 0 is not restored after Restore or if any error occurs.
+func TestFd0(t *testing.T) {
+	// Retrieve the file descriptor 0 before mocking Stdin
+	var before, after syscall.Stat_t
+	if e := syscall.Fstat(0, &before); e != nil {
+		t.Skip(tserr.Op(&tserr.OpArgs{Op: "Fstat", Fn: "file descriptor 0", Err: e}))
+	}
+	// Retrieve a new mocked Stdin installed at file descriptor 0
+	stdin, e := tsmock.NewStdin(tsmock.WithFd0(true), tsmock.WithVisibility(false), tsmock.WithString("Aragorn\n"))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "file descriptor 0", Err: e}))
+	}
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Read the input from file descriptor 0. The test fails if Read returns an error.
+	buf := make([]byte, 64)
+	n, e := syscall.Read(0, buf)
+	if e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Read", Fn: "file descriptor 0", Err: e}))
+	}
+	// Restore Stdin. The test fails if Restore returns an error.
+	if e := stdin.Restore(); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Restore", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if the input does not equal the contents
+	if n < 0 {
+		n = 0
+	}
+	if string(buf[:n]) != "Aragorn\n" {
+		t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "input", Actual: string(buf[:n]), Want: "Aragorn\n"}))
+	}
+	// Retrieve the file descriptor 0 after Restore. The test fails if Fstat returns an error.
+	if e := syscall.Fstat(0, &after); e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "Fstat", Fn: "file descriptor 0", Err: e}))
+	}
+	// The test fails if file descriptor 0 is not restored
+	if (before.Dev != after.Dev) || (before.Ino != after.Ino) {
+		t.Error(tserr.NotEqual(&tserr.NotEqualArgs{X: "file descriptor 0 after Restore", Y: "file descriptor 0 before"}))
+	}
+}
@@ -0,0 +1,25 @@
+//go:build !linux
+
+// Fd_other.go provides a fallback for platforms without support of installing the mocked Stdin at file descriptor 0.
+//
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock
+
+// Import go standard library package os and tserr
+import (
+	"os" // os
+
+	"github.com/thorstenrie/tserr" // tserr
+)
+
+// installFd0 returns an error, since installing at file descriptor 0 is not supported on this platform.
+func installFd0(f *os.File) (int, error) {
+	return -1, tserr.NotAvailable(&tserr.NotAvailableArgs{S: "file descriptor 0", Err: tserr.Forbidden("platform")})
+}
+
+// restoreFd0 returns an error, since installing at file descriptor 0 is not supported on this platform.
+func restoreFd0(s int) error {
+	return tserr.NotAvailable(&tserr.NotAvailableArgs{S: "file descriptor 0", Err: tserr.Forbidden("platform")})
+}
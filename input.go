@@ -0,0 +1,89 @@
+// Input.go provides further input sources for the mocked Stdin. Besides a file, the input can be set to
+// an io.Reader, a string, a byte slice or a file in a file system, for example an embed.FS.
+//
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock
+
+// Import go standard library packages and tserr
+import (
+	"bytes"   // bytes
+	"io"      // io
+	"io/fs"   // io/fs
+	"strings" // strings
+
+	"github.com/thorstenrie/tserr" // tserr
+)
+
+// SetReader sets the input of the mocked Stdin to r and replaces os.Stdin. The reader r is not closed by the mocked Stdin.
+// If a previous mock run is still being executed, SetReader returns an error. SetReader also returns an error, if
+// another mocked Stdin instance replaces os.Stdin.
+func (stdin *MockStdin) SetReader(r io.Reader) error {
+	// Set the input to r, which is not closed by the mocked Stdin
+	return stdin.setInput(r, nil)
+}
+
+// SetString sets the input of the mocked Stdin to s and replaces os.Stdin. See SetReader.
+func (stdin *MockStdin) SetString(s string) error {
+	// Set the input to a reader on s
+	return stdin.SetReader(strings.NewReader(s))
+}
+
+// SetBytes sets the input of the mocked Stdin to b and replaces os.Stdin. See SetReader.
+func (stdin *MockStdin) SetBytes(b []byte) error {
+	// Set the input to a reader on b
+	return stdin.SetReader(bytes.NewReader(b))
+}
+
+// SetFS sets the input of the mocked Stdin to the file name in the file system fsys and replaces os.Stdin. The file is
+// closed by Restore. It returns an error, if the file cannot be opened. See SetReader.
+func (stdin *MockStdin) SetFS(fsys fs.FS, name string) error {
+	// Return an error if fsys is nil
+	if fsys == nil {
+		return tserr.NilPtr()
+	}
+	// Open file name in fsys
+	f, e := fsys.Open(name)
+	// Return an error if Open fails
+	if e != nil {
+		return tserr.Op(&tserr.OpArgs{Op: "Open", Fn: name, Err: e})
+	}
+	// Set the input to f, which is closed by the mocked Stdin
+	if e := stdin.setInput(f, f); e != nil {
+		// Close f, if setting the input fails
+		f.Close()
+		return e
+	}
+	// Return nil
+	return nil
+}
+
+// WithReader returns an option to set the input of the mocked Stdin to r. The option replaces os.Stdin. See SetReader.
+func WithReader(r io.Reader) Option {
+	return inputOption(func(stdin *MockStdin) error {
+		return stdin.SetReader(r)
+	})
+}
+
+// WithString returns an option to set the input of the mocked Stdin to s. The option replaces os.Stdin. See SetString.
+func WithString(s string) Option {
+	return inputOption(func(stdin *MockStdin) error {
+		return stdin.SetString(s)
+	})
+}
+
+// WithBytes returns an option to set the input of the mocked Stdin to b. The option replaces os.Stdin. See SetBytes.
+func WithBytes(b []byte) Option {
+	return inputOption(func(stdin *MockStdin) error {
+		return stdin.SetBytes(b)
+	})
+}
+
+// WithFS returns an option to set the input of the mocked Stdin to the file name in the file system fsys. The option
+// replaces os.Stdin. See SetFS.
+func WithFS(fsys fs.FS, name string) Option {
+	return inputOption(func(stdin *MockStdin) error {
+		return stdin.SetFS(fsys, name)
+	})
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock_test
+
+// Import go standard library packages as well as tserr, tsfio and tsmock
+import (
+	"context"        // context
+	"io"             // io
+	"strings"        // strings
+	"testing"        // testing
+	"testing/fstest" // fstest
+
+	"github.com/thorstenrie/tserr"  // tserr
+	"github.com/thorstenrie/tsfio"  // tsfio
+	"github.com/thorstenrie/tsmock" // tsmock
+)
+
+// TestSetFile tests Stdin with the input set to the test file. The test fails if the
+// contents received from stdin does not equal the contents of the test file or if any error occurs.
+func TestSetFile(t *testing.T) {
+	// Write the contents to the testfile
+	tsfio.WriteSingleStr(testfile, contents)
+	// Defer removing the testfile
+	defer tsfio.RemoveFile(testfile)
+	// Open the testfile
+	fs, err := tsfio.OpenFile(testfile)
+	// The test fails if OpenFile returns an error
+	if err != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "OpenFile", Fn: string(testfile), Err: err}))
+	}
+	// Defer closing the testfile
+	defer fs.Close()
+	// Set stdin to fs
+	if e := tsmock.Stdin.Set(fs); e != nil {
+		// The test fails if Set returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Set", Fn: string(testfile), Err: e}))
+	}
+	// Run the mocked Stdin and compare the received input with the contents
+	testInput(contents, t)
+}
+
+// TestSetBytes tests Stdin with the input set to a byte slice. The test fails if the
+// contents received from stdin does not equal the byte slice or if any error occurs.
+func TestSetBytes(t *testing.T) {
+	// Set stdin to the contents as byte slice
+	if e := tsmock.Stdin.SetBytes([]byte(contents)); e != nil {
+		// The test fails if SetBytes returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "SetBytes", Fn: "contents", Err: e}))
+	}
+	// Run the mocked Stdin and compare the received input with the contents
+	testInput(contents, t)
+}
+
+// TestSetReader tests Stdin with the input set to an io.Reader. The test fails if the
+// contents received from stdin does not equal the contents of the reader or if any error occurs.
+func TestSetReader(t *testing.T) {
+	// Set stdin to a reader on the contents, hiding the underlying type
+	if e := tsmock.Stdin.SetReader(io.MultiReader(strings.NewReader(contents))); e != nil {
+		// The test fails if SetReader returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "SetReader", Fn: "contents", Err: e}))
+	}
+	// Run the mocked Stdin and compare the received input with the contents
+	testInput(contents, t)
+}
+
+// TestSetFS tests Stdin with the input set to a file in a file system. The test fails if the
+// contents received from stdin does not equal the contents of the file or if any error occurs.
+func TestSetFS(t *testing.T) {
+	// File system with the test file
+	fsys := fstest.MapFS{string(testfile): &fstest.MapFile{Data: []byte(contents)}}
+	// Set stdin to the test file in fsys
+	if e := tsmock.Stdin.SetFS(fsys, string(testfile)); e != nil {
+		// The test fails if SetFS returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "SetFS", Fn: string(testfile), Err: e}))
+	}
+	// Run the mocked Stdin and compare the received input with the contents
+	testInput(contents, t)
+}
+
+// TestSetFSNotExistent tests if SetFS returns an error in case of a nil file system or a
+// non-existent file. The test fails if SetFS returns nil.
+func TestSetFSNotExistent(t *testing.T) {
+	// The test fails if SetFS returns nil for a nil file system
+	if e := tsmock.Stdin.SetFS(nil, string(testfile)); e == nil {
+		t.Error(tserr.NilFailed("SetFS"))
+	}
+	// The test fails if SetFS returns nil for a non-existent file
+	if e := tsmock.Stdin.SetFS(fstest.MapFS{}, string(testfile)); e == nil {
+		t.Error(tserr.NilFailed("SetFS"))
+	}
+}
+
+// TestSetReaderNil tests if SetReader returns an error in case of nil. The test
+// fails if SetReader returns nil.
+func TestSetReaderNil(t *testing.T) {
+	if e := tsmock.Stdin.SetReader(nil); e == nil {
+		t.Error(tserr.NilFailed("SetReader"))
+	}
+}
+
+// TestWithBytes tests a new mocked Stdin with the input set to a byte slice by option WithBytes. The test fails if
+// the contents received from stdin does not equal the byte slice or if any error occurs.
+func TestWithBytes(t *testing.T) {
+	// Run a new mocked Stdin with the contents as byte slice and compare the received input with the contents
+	testWithInput(tsmock.WithBytes([]byte(contents)), contents, t)
+}
+
+// TestWithFS tests a new mocked Stdin with the input set to a file in a file system by option WithFS. The test fails
+// if the contents received from stdin does not equal the contents of the file or if any error occurs.
+func TestWithFS(t *testing.T) {
+	// File system with the test file
+	fsys := fstest.MapFS{string(testfile): &fstest.MapFile{Data: []byte(contents)}}
+	// Run a new mocked Stdin with the test file in fsys and compare the received input with the contents
+	testWithInput(tsmock.WithFS(fsys, string(testfile)), contents, t)
+}
+
+// TestWithFSNotExistent tests if NewStdin returns an error in case option WithFS is set to a non-existent file.
+// The test fails if NewStdin returns nil.
+func TestWithFSNotExistent(t *testing.T) {
+	// Retrieve a new mocked Stdin with a non-existent file
+	stdin, e := tsmock.NewStdin(tsmock.WithFS(fstest.MapFS{}, string(testfile)))
+	// The test fails if NewStdin returns nil
+	if e == nil {
+		stdin.Restore()
+		t.Error(tserr.NilFailed("NewStdin"))
+	}
+}
+
+// testWithInput retrieves a new mocked Stdin with the input set by option opt, runs it with visibility set to false
+// and compares the input received from os.Stdin with the reference string ref. The mocked Stdin is restored afterwards.
+// The test fails in case of an error.
+func testWithInput(opt tsmock.Option, ref string, t *testing.T) {
+	// Panic if t is nil
+	if t == nil {
+		panic(tserr.NilPtr())
+	}
+	// Retrieve a new mocked Stdin with the input set by opt, visibility set to false and no input delay
+	stdin, e := tsmock.NewStdin(opt, tsmock.WithVisibility(false), tsmock.WithDelay(0))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Defer restoring the mocked Stdin. The test fails if Restore returns an error.
+	defer func() {
+		if e := stdin.Restore(); e != nil {
+			t.Error(tserr.Op(&tserr.OpArgs{Op: "Restore", Fn: "Stdin", Err: e}))
+		}
+	}()
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+		return
+	}
+	// Scan stdin and compare the retrieved text with the reference string ref
+	if e := testStdinEval(ref, t); e != nil {
+		t.Error(e)
+	}
+}
+
+// testInput runs the mocked Stdin with visibility set to false and compares the input received from
+// os.Stdin with the reference string ref. Stdin is restored afterwards. The test fails in case of an error.
+func testInput(ref string, t *testing.T) {
+	// Panic if t is nil
+	if t == nil {
+		panic(tserr.NilPtr())
+	}
+	// Defer restoring Stdin. The test fails if Stdin has an error in Err.
+	defer testStdinClose(t)
+	// Set visibility of stdin to false
+	tsmock.Stdin.Visibility(false)
+	// Set input delay to zero
+	if e := tsmock.Stdin.Delay(0); e != nil {
+		// The test fails if Delay returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Delay", Fn: "Stdin", Err: e}))
+	}
+	// Mock Stdin
+	if e := tsmock.Stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+		return
+	}
+	// Scan stdin and compare the retrieved text with the reference string ref.
+	if e := testStdinEval(ref, t); e != nil {
+		t.Error(e)
+	}
+}
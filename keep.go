@@ -0,0 +1,51 @@
+// Keep.go provides holding the mocked Stdin open after the end of the input. By default, the program reads end-of-file as soon as
+// all input has been written. Real users rarely end the input, so programs treating end-of-file as quit behave differently under
+// test. If enabled, the mocked Stdin is held open after the last line until SendEOF, Restore or cancellation, so that the program
+// waits for more input like at an idle prompt.
+//
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock
+
+// Import go standard library package context
+import (
+	"context" // context
+)
+
+// KeepOpen holds the mocked Stdin open after the end of the input, if k is true. The run continues after the last line until SendEOF is
+// called, the run is canceled or Restore is called. Then, end-of-file is sent. The directive #! eof sends end-of-file without holding open.
+// In strict mode, reads of the program waiting for more input are not reported with ErrOverRead. KeepOpen returns an error if the mocked
+// Stdin is executing.
+func (stdin *MockStdin) KeepOpen(k bool) error {
+	// Return an error if mocked Stdin is executing
+	if e := stdin.notRunning(); e != nil {
+		return e
+	}
+	// Set holding open to k
+	stdin.keep.Set(k)
+	// Return nil
+	return nil
+}
+
+// WithKeepOpen returns an option to hold the mocked Stdin open after the end of the input, if k is true. See KeepOpen.
+func WithKeepOpen(k bool) Option {
+	return func(stdin *MockStdin) error {
+		return stdin.KeepOpen(k)
+	}
+}
+
+// holdOpen waits until SendEOF is called or the context is canceled, if holding open is enabled. Otherwise, it returns immediately.
+func (stdin *MockStdin) holdOpen(ctx context.Context) {
+	// Retrieve the hold of the current input
+	h := stdin.hold.Get()
+	// Return if holding open is disabled or the hold is not set
+	if !stdin.keep.Get() || (h == nil) {
+		return
+	}
+	// Wait until SendEOF is called or the context is canceled
+	select {
+	case <-h.done:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock_test
+
+// Import go standard library packages as well as tserr and tsmock
+import (
+	"bufio"   // bufio
+	"context" // context
+	"errors"  // errors
+	"io"      // io
+	"os"      // os
+	"testing" // testing
+	"time"    // time
+
+	"github.com/thorstenrie/tserr"  // tserr
+	"github.com/thorstenrie/tsmock" // tsmock
+)
+
+// TestKeepOpen tests holding the mocked Stdin open after the end of the input until SendEOF. The test fails if the run ends
+// before SendEOF, if os.Stdin does not end after SendEOF, if a second SendEOF does not fail or if any error occurs.
+func TestKeepOpen(t *testing.T) {
+	// Retrieve a new mocked Stdin held open
+	stdin, e := tsmock.NewStdin(tsmock.WithKeepOpen(true), tsmock.WithVisibility(false), tsmock.WithString("Aragorn\n"))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Defer restoring Stdin
+	defer stdin.Restore()
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Retrieve a reader on os.Stdin
+	r := bufio.NewReader(os.Stdin)
+	// The test fails if the line read does not equal the input
+	testSendRead(r, "Aragorn\n", t)
+	// The test fails if the run ends before SendEOF
+	select {
+	case <-stdin.Done():
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "Done", Actual: "closed", Want: "open"}))
+	case <-time.After(20 * time.Millisecond):
+	}
+	// End holding open. The test fails if SendEOF returns an error.
+	if e := stdin.SendEOF(); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "SendEOF", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if os.Stdin does not end
+	if _, e := r.ReadString('\n'); e != io.EOF {
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "ReadString", Actual: "not io.EOF", Want: "io.EOF"}))
+	}
+	// The test fails if the run returns an error
+	if e := stdin.Wait(context.Background()); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Wait", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if the run did not finish
+	if !stdin.Result().Finished {
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "Result", Actual: "not finished", Want: "finished"}))
+	}
+	// The test fails if a second SendEOF does not return ErrSendClosed
+	if e := stdin.SendEOF(); !errors.Is(e, tsmock.ErrSendClosed) {
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "SendEOF", Actual: "not ErrSendClosed", Want: "ErrSendClosed"}))
+	}
+}
+
+// TestKeepOpenRestore tests Restore to end holding the mocked Stdin open. The test fails if Restore returns an error or if
+// the run is not reported as finished.
+func TestKeepOpenRestore(t *testing.T) {
+	// Retrieve a new mocked Stdin held open
+	stdin, e := tsmock.NewStdin(tsmock.WithKeepOpen(true), tsmock.WithVisibility(false), tsmock.WithString("Aragorn\n"))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Read the line. The test fails if the line read does not equal the input.
+	testSendRead(bufio.NewReader(os.Stdin), "Aragorn\n", t)
+	// Restore Stdin. The test fails if Restore returns an error.
+	if e := stdin.Restore(); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Restore", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if the run is not reported as finished
+	if res := stdin.Result(); !res.Finished || res.Cancelled {
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "Result", Actual: "not finished", Want: "finished"}))
+	}
+	// The test fails if SendEOF returns nil after Restore
+	if e := stdin.SendEOF(); e == nil {
+		t.Error(tserr.NilFailed("SendEOF"))
+	}
+}
+
+// TestKeepOpenInteractive tests holding the mocked Stdin open with input sent with Send. The test fails if os.Stdin does not end
+// after SendEOF or if any error occurs.
+func TestKeepOpenInteractive(t *testing.T) {
+	// Retrieve a new mocked Stdin held open with interactive input
+	stdin, e := tsmock.NewStdin(tsmock.WithKeepOpen(true), tsmock.WithVisibility(false), tsmock.WithInteractive())
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Defer restoring Stdin
+	defer stdin.Restore()
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Send a line and end the input. The test fails if SendLine or SendEOF returns an error.
+	if e := errors.Join(stdin.SendLine("Gandalf"), stdin.SendEOF()); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Send", Fn: "Gandalf", Err: e}))
+	}
+	// Read all input. The test fails if ReadAll returns an error.
+	b, e := io.ReadAll(os.Stdin)
+	if e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "ReadAll", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if the input does not equal the line sent
+	if string(b) != "Gandalf\n" {
+		t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "input", Actual: string(b), Want: "Gandalf\n"}))
+	}
+}
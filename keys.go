@@ -0,0 +1,187 @@
+// Keys.go provides a key notation for special keys in the input of the mocked Stdin. Keys are written in angle brackets,
+// e.g., <Up><Up><Enter>, and translated into the byte sequences of an xterm compatible terminal. Control keys are written
+// as <C-d> and keys with the Alt modifier as <M-x>. Key names are not case-sensitive. A literal < is written as <lt>.
+// Text in angle brackets, which is not a key name, is left unchanged.
+//
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock
+
+// Import go standard library packages
+import (
+	"bytes"   // bytes
+	"io"      // io
+	"strings" // strings
+)
+
+// keys maps the lower case key names to the byte sequences of an xterm compatible terminal.
+var keys = map[string]string{
+	"up":       "\x1b[A",
+	"down":     "\x1b[B",
+	"right":    "\x1b[C",
+	"left":     "\x1b[D",
+	"home":     "\x1b[H",
+	"end":      "\x1b[F",
+	"pageup":   "\x1b[5~",
+	"pagedown": "\x1b[6~",
+	"insert":   "\x1b[2~",
+	"del":      "\x1b[3~",
+	"delete":   "\x1b[3~",
+	"enter":    "\r",
+	"cr":       "\r",
+	"nl":       "\n",
+	"tab":      "\t",
+	"s-tab":    "\x1b[Z",
+	"bs":       "\x7f",
+	"esc":      "\x1b",
+	"space":    " ",
+	"lt":       "<",
+	"f1":       "\x1bOP",
+	"f2":       "\x1bOQ",
+	"f3":       "\x1bOR",
+	"f4":       "\x1bOS",
+	"f5":       "\x1b[15~",
+	"f6":       "\x1b[17~",
+	"f7":       "\x1b[18~",
+	"f8":       "\x1b[19~",
+	"f9":       "\x1b[20~",
+	"f10":      "\x1b[21~",
+	"f11":      "\x1b[23~",
+	"f12":      "\x1b[24~",
+}
+
+// maxKey is the length of the longest key notation including the angle brackets, e.g., <pagedown>.
+const maxKey = 10
+
+// keyReader is an io.Reader, which translates the key notation in the input as soon as it is read. An incomplete key notation at the
+// end of the input read is kept until it is completed by the next read or the input ends.
+type keyReader struct {
+	r   io.Reader // Input
+	in  []byte    // Incomplete key notation at the end of the input read, not translated yet
+	buf []byte    // Translated input not read yet
+	err error     // Error reading the input, if any
+}
+
+// KeyNotation enables the translation of the key notation in the input of the mocked Stdin, if k is true. Keys in angle brackets,
+// e.g., <Up>, <Tab>, <C-d> or <BS>, are translated into the byte sequences of an xterm compatible terminal. In line mode, a carriage
+// return at the end of a line is removed with the line ending. Therefore, the key notation is usually used in raw mode or with a
+// pseudo-terminal. KeyNotation returns an error if the mocked Stdin is executing.
+func (stdin *MockStdin) KeyNotation(k bool) error {
+	// Return an error if mocked Stdin is executing
+	if e := stdin.notRunning(); e != nil {
+		return e
+	}
+	// Set the translation of the key notation to k
+	stdin.keys.Set(k)
+	// Return nil
+	return nil
+}
+
+// WithKeyNotation returns an option to enable the translation of the key notation in the input of the mocked Stdin, if k is true. See KeyNotation.
+func WithKeyNotation(k bool) Option {
+	return func(stdin *MockStdin) error {
+		return stdin.KeyNotation(k)
+	}
+}
+
+// Keys returns s with the key notation translated into the byte sequences of an xterm compatible terminal. For example,
+// Keys("<Up><Up><Enter>") returns "\x1b[A\x1b[A\r".
+func Keys(s string) string {
+	// Return the translated s
+	return string(translateKeys([]byte(s)))
+}
+
+// key returns the byte sequence of the key with name n and true. It returns false, if n is not a key name.
+func key(n string) (string, bool) {
+	// Return the byte sequence of a named key
+	if k, ok := keys[strings.ToLower(n)]; ok {
+		return k, true
+	}
+	// Return the control character of a control key, e.g., C-d
+	if c, ok := strings.CutPrefix(strings.ToLower(n), "c-"); ok && (len(c) == 1) && (c[0] >= 'a') && (c[0] <= 'z') {
+		return string(rune(c[0] - 'a' + 1)), true
+	}
+	// Return the escape character followed by the key for a key with the Alt modifier, e.g., M-x
+	if (len(n) == 3) && strings.EqualFold(n[:2], "m-") {
+		return "\x1b" + n[2:], true
+	}
+	// Return false otherwise
+	return "", false
+}
+
+// translateKeys returns p with the key notation translated into the byte sequences of an xterm compatible terminal.
+func translateKeys(p []byte) []byte {
+	// Translated p
+	var t []byte
+	for {
+		// Retrieve the next opening angle bracket
+		i := bytes.IndexByte(p, '<')
+		// Retrieve the closing angle bracket after the opening angle bracket
+		j := -1
+		if i >= 0 {
+			j = bytes.IndexByte(p[i:], '>')
+		}
+		// Return t with the remaining p, if no key notation is left
+		if j < 0 {
+			return append(t, p...)
+		}
+		// Append p up to the opening angle bracket
+		t = append(t, p[:i]...)
+		// Append the byte sequence of the key, if the text in angle brackets is a key name
+		if k, ok := key(string(p[i+1 : i+j])); ok {
+			t = append(t, k...)
+			p = p[i+j+1:]
+			continue
+		}
+		// Append the opening angle bracket and continue after it otherwise
+		t = append(t, '<')
+		p = p[i+1:]
+	}
+}
+
+// newKeyReader returns a new keyReader translating the key notation in r.
+func newKeyReader(r io.Reader) *keyReader {
+	// Return a new keyReader
+	return &keyReader{r: r}
+}
+
+// Read reads the translated input into p. It translates the input as soon as it is read, except for an incomplete key notation at the end.
+func (k *keyReader) Read(p []byte) (int, error) {
+	// Translate the next input, if all translated input has been read
+	for (len(k.buf) == 0) && (k.err == nil) {
+		// Read the next input after the incomplete key notation
+		q := make([]byte, 4096)
+		n, e := k.r.Read(q)
+		k.in, k.err = append(k.in, q[:n]...), e
+		// Keep an incomplete key notation at the end, unless the input ended
+		m := len(k.in)
+		if k.err == nil {
+			m = incomplete(k.in)
+		}
+		// Translate the input up to the incomplete key notation
+		k.buf, k.in = translateKeys(k.in[:m]), append([]byte(nil), k.in[m:]...)
+	}
+	// Return the error, if all translated input has been read
+	if len(k.buf) == 0 {
+		return 0, k.err
+	}
+	// Copy the translated input into p
+	n := copy(p, k.buf)
+	k.buf = k.buf[n:]
+	// Return the number of bytes copied
+	return n, nil
+}
+
+// incomplete returns the index of an incomplete key notation at the end of p. It returns the length of p, if p does not end with
+// an opening angle bracket followed by a possible key name without a closing angle bracket.
+func incomplete(p []byte) int {
+	// Retrieve the last opening angle bracket
+	i := bytes.LastIndexByte(p, '<')
+	// Return the length of p, if the text after the last opening angle bracket cannot be an incomplete key notation
+	if (i < 0) || (len(p)-i >= maxKey) || bytes.ContainsAny(p[i:], ">\n") {
+		return len(p)
+	}
+	// Return the index of the incomplete key notation
+	return i
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock_test
+
+// Import go standard library packages as well as tserr and tsmock
+import (
+	"context" // context
+	"io"      // io
+	"os"      // os
+	"testing" // testing
+
+	"github.com/thorstenrie/tserr"  // tserr
+	"github.com/thorstenrie/tsmock" // tsmock
+)
+
+// TestKeys tests the translation of the key notation with Keys. The test fails if the translation does not equal the expected byte sequence.
+func TestKeys(t *testing.T) {
+	// Key notations and the expected byte sequences
+	tests := map[string]string{
+		"<Up><Up><Enter>":  "\x1b[A\x1b[A\r",
+		"ab<BS>c<Tab>":     "ab\x7fc\t",
+		"<C-d><c-C><Esc>":  "\x04\x03\x1b",
+		"<M-x><F1>":        "\x1bx\x1bOP",
+		"<lt>Up> <html>":   "<Up> <html>",
+		"<<Down>":          "<\x1b[B",
+		"no keys <at all":  "no keys <at all",
+		"<C-dd><S-Tab><>":  "<C-dd>\x1b[Z<>",
+		"Gandalf<Space>ok": "Gandalf ok",
+	}
+	for k, want := range tests {
+		// The test fails if the translation does not equal the expected byte sequence
+		if got := tsmock.Keys(k); got != want {
+			t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: k, Actual: got, Want: want}))
+		}
+	}
+}
+
+// TestKeyNotation tests the translation of the key notation in the input of the mocked Stdin in raw mode. The test fails if
+// the input received from os.Stdin does not equal the translated input.
+func TestKeyNotation(t *testing.T) {
+	// Input in key notation
+	in := "<Down><Down><Enter>\nab<BS>c<Enter>\n<C-d>"
+	// Retrieve a new mocked Stdin in raw mode with the key notation
+	stdin, e := tsmock.NewStdin(tsmock.WithVisibility(false), tsmock.WithRaw(true), tsmock.WithKeyNotation(true), tsmock.WithString(in))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Read all input. The test fails if ReadAll returns an error.
+	b, e := io.ReadAll(os.Stdin)
+	if e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "ReadAll", Fn: "Stdin", Err: e}))
+	}
+	// Restore Stdin. The test fails if Restore returns an error.
+	if e := stdin.Restore(); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Restore", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if the input does not equal the translated input
+	if want := tsmock.Keys(in); string(b) != want {
+		t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "input", Actual: string(b), Want: want}))
+	}
+}
@@ -0,0 +1,211 @@
+// Kind.go provides the kind of file the program sees as os.Stdin. By default, the mocked Stdin is a pipe. It can also be
+// a regular temporary file, which is seekable, the null device, a closed file or a pseudo-terminal. Programs often check
+// the kind with os.Stdin.Stat, e.g., to read piped input or to prompt otherwise, so that both cases can be tested.
+//
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock
+
+// Import go standard library packages and tserr
+import (
+	"fmt" // fmt
+	"io"  // io
+	"os"  // os
+
+	"github.com/thorstenrie/tserr" // tserr
+)
+
+// Kind is the kind of file the program sees as os.Stdin.
+type Kind int
+
+const (
+	KindPipe    Kind = iota // A pipe, which is the default
+	KindFile                // A regular temporary file, which is seekable
+	KindDevNull             // The null device, the input is discarded
+	KindClosed              // A closed file, the input is discarded
+	KindTty                 // A pseudo-terminal, see Pty
+)
+
+// String returns the name of the kind k.
+func (k Kind) String() string {
+	switch k {
+	case KindPipe:
+		return "Pipe"
+	case KindFile:
+		return "File"
+	case KindDevNull:
+		return "DevNull"
+	case KindClosed:
+		return "Closed"
+	case KindTty:
+		return "Tty"
+	}
+	// Return the number of an unknown kind
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Kind sets the kind of file the program sees as os.Stdin to k. The file is opened by the next call of Set or any other input setter.
+// With KindFile, the whole input is written into a regular temporary file by the input setter, before the file replaces os.Stdin. The
+// input is written at once without delay, typing, echo, directives or signals, and Run only reports the result. Input sent with Send or
+// received from a channel is not supported with KindFile. With KindDevNull and KindClosed, the input is discarded and
+// os.Stdin is the null device or a closed file. KindTty is the same as Pty(true). Strict mode is only supported for pipes and
+// pseudo-terminals. Kind returns an error if k is unknown, if k is KindClosed and the installation at file descriptor 0 is enabled
+// with Fd0 or if the mocked Stdin is executing.
+func (stdin *MockStdin) Kind(k Kind) error {
+	// Return an error if k is unknown
+	if (k < KindPipe) || (k > KindTty) {
+		return tserr.NotExistent(k.String())
+	}
+	// Return an error if k is KindClosed and the installation at file descriptor 0 is enabled
+	if (k == KindClosed) && stdin.fd0.Get() {
+		return errFd0Closed()
+	}
+	// Return an error if mocked Stdin is executing
+	if e := stdin.notRunning(); e != nil {
+		return e
+	}
+	// Set the kind to k
+	stdin.kind.Set(k)
+	// Return nil
+	return nil
+}
+
+// WithKind returns an option to set the kind of file the program sees as os.Stdin to k. See Kind.
+func WithKind(k Kind) Option {
+	return func(stdin *MockStdin) error {
+		return stdin.Kind(k)
+	}
+}
+
+// open opens the read end and the write end of the mocked Stdin of the kind set with Kind. It returns an error,
+// if opening fails.
+func (stdin *MockStdin) open() error {
+	// Retrieve the kind and store it as kind of the opened mocked Stdin
+	k := stdin.kind.Get()
+	stdin.opened = k
+	switch k {
+	// Open a new pseudo-terminal
+	case KindTty:
+		return stdin.openTerminal()
+	// Open a new regular temporary file
+	case KindFile:
+		return stdin.openFile()
+	// Open the null device
+	case KindDevNull:
+		return stdin.openDevNull(false)
+	// Open the null device and close the read end
+	case KindClosed:
+		return stdin.openDevNull(true)
+	}
+	// Retrieve a new pipe
+	var e error
+	stdin.r, stdin.w, e = os.Pipe()
+	// Return an error if retrieving a new pipe fails
+	if (e != nil) || (stdin.w == nil) || (stdin.r == nil) {
+		return tserr.NotAvailable(&tserr.NotAvailableArgs{S: "os.Pipe", Err: e})
+	}
+	// Open the read end in blocking mode in strict mode to detect reads past the end of the input
+	if stdin.strict.Get() {
+		if stdin.r, e = blockingRead(stdin.r); e != nil {
+			stdin.w.Close()
+			return e
+		}
+	}
+	// Return nil
+	return nil
+}
+
+// openFile opens a new regular temporary file. The file is opened for writing as write end and for reading as read end.
+// The file is removed, when it is closed. It returns an error, if opening the file fails.
+func (stdin *MockStdin) openFile() error {
+	// Create a new temporary file for writing
+	w, e := os.CreateTemp("", "tsmock-stdin-*")
+	// Return an error if creating the file fails
+	if e != nil {
+		return tserr.NotAvailable(&tserr.NotAvailableArgs{S: "temporary file", Err: e})
+	}
+	// Open the file for reading with an own offset
+	r, e := os.Open(w.Name())
+	// Return an error if opening the file fails
+	if e != nil {
+		w.Close()
+		os.Remove(w.Name())
+		return tserr.NotAvailable(&tserr.NotAvailableArgs{S: w.Name(), Err: e})
+	}
+	// Set the write end, the read end and the file to be removed
+	stdin.w, stdin.r, stdin.tmp = w, r, w.Name()
+	// Return nil
+	return nil
+}
+
+// openDevNull opens the null device for writing as write end and for reading as read end. The read end is closed, if c is true.
+// It returns an error, if opening the null device fails.
+func (stdin *MockStdin) openDevNull(c bool) error {
+	// Open the null device for writing
+	w, e := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	// Return an error if opening the null device fails
+	if e != nil {
+		return tserr.NotAvailable(&tserr.NotAvailableArgs{S: os.DevNull, Err: e})
+	}
+	// Open the null device for reading
+	r, e := os.Open(os.DevNull)
+	// Return an error if opening the null device fails
+	if e != nil {
+		w.Close()
+		return tserr.NotAvailable(&tserr.NotAvailableArgs{S: os.DevNull, Err: e})
+	}
+	// Close the read end, if c is true
+	if c {
+		r.Close()
+	}
+	// Set the write end and the read end
+	stdin.w, stdin.r = w, r
+	// Return nil
+	return nil
+}
+
+// fill writes the whole input into the regular temporary file and stores the result for Run. The key notation is translated, if
+// enabled. It returns an error, if the input is sent dynamically or if reading the input or writing the file fails.
+func (stdin *MockStdin) fill() error {
+	// Return an error if the input is sent dynamically, since it would never end
+	if _, ok := stdin.in.(stopper); ok {
+		return tserr.Forbidden("dynamic input with KindFile")
+	}
+	// Retrieve the input
+	var in io.Reader = stdin.in
+	// Translate the key notation in the input, if enabled
+	if stdin.keys.Get() {
+		in = newKeyReader(in)
+	}
+	// Read the whole input
+	b, e := io.ReadAll(in)
+	// Return an error if reading the input fails
+	if e != nil {
+		return tserr.Op(&tserr.OpArgs{Op: "read", Fn: "input", Err: e})
+	}
+	// Write the input into the file
+	if _, e := stdin.w.Write(b); e != nil {
+		return tserr.Op(&tserr.OpArgs{Op: "write", Fn: stdin.tmp, Err: e})
+	}
+	// Close the write end of the file
+	if e := stdin.w.Close(); e != nil {
+		return tserr.Op(&tserr.OpArgs{Op: "close", Fn: stdin.tmp, Err: e})
+	}
+	// Record the written bytes and lines
+	stdin.record(b)
+	// Store the result with the written bytes and lines, including a last line without a newline
+	stdin.filled = Result{Finished: true}
+	stdin.filled.count(b)
+	if (len(b) > 0) && (b[len(b)-1] != '\n') {
+		stdin.filled.Lines++
+	}
+	// Return nil
+	return nil
+}
+
+// blocking returns true, if a write to the opened mocked Stdin may block, because the program does not read the input.
+func (stdin *MockStdin) blocking() bool {
+	// Return true for a pipe or a pseudo-terminal
+	return stdin.tty || (stdin.opened == KindPipe)
+}
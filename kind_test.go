@@ -0,0 +1,227 @@
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock_test
+
+// Import go standard library packages as well as tserr and tsmock
+import (
+	"context" // context
+	"errors"  // errors
+	"io"      // io
+	"os"      // os
+	"testing" // testing
+	"time"    // time
+
+	"github.com/thorstenrie/tserr"  // tserr
+	"github.com/thorstenrie/tsmock" // tsmock
+)
+
+// TestKindPipe tests the mocked Stdin of the default kind. The test fails if os.Stdin is not a named pipe,
+// if the received input does not equal the contents or if any error occurs.
+func TestKindPipe(t *testing.T) {
+	// Run a new mocked Stdin of the default kind
+	m, b := testKind(tsmock.KindPipe, t)
+	// The test fails if os.Stdin is not a named pipe
+	if m&os.ModeNamedPipe == 0 {
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "Stat", Actual: m.String(), Want: "named pipe"}))
+	}
+	// The test fails if the received input does not equal the contents
+	if b != contents {
+		t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "input", Actual: b, Want: contents}))
+	}
+}
+
+// TestKindFile tests the mocked Stdin as regular file. The test fails if os.Stdin is not a regular file, if the received
+// input does not equal the contents, if os.Stdin is not seekable or if any error occurs.
+func TestKindFile(t *testing.T) {
+	// Run a new mocked Stdin as regular file
+	m, b := testKind(tsmock.KindFile, t)
+	// The test fails if os.Stdin is not a regular file
+	if !m.IsRegular() {
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "Stat", Actual: m.String(), Want: "regular file"}))
+	}
+	// The test fails if the received input does not equal the contents
+	if b != contents {
+		t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "input", Actual: b, Want: contents}))
+	}
+}
+
+// TestKindFileImmediate tests that the whole input is in the regular file right after the input has been set, even with a delay.
+// The test fails if the input read before Run does not equal the contents, if the result does not report all lines or if any error occurs.
+func TestKindFileImmediate(t *testing.T) {
+	// Retrieve a new mocked Stdin as regular file with a delay of one minute
+	stdin, e := tsmock.NewStdin(tsmock.WithKind(tsmock.KindFile), tsmock.WithDelay(time.Minute), tsmock.WithVisibility(false), tsmock.WithString(contents))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "File", Err: e}))
+	}
+	// Defer restoring Stdin
+	defer stdin.Restore()
+	// Read the input before Run. The test fails if ReadAll returns an error.
+	b, e := io.ReadAll(os.Stdin)
+	if e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "ReadAll", Fn: "File", Err: e}))
+	}
+	// The test fails if the input does not equal the contents
+	if string(b) != contents {
+		t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "input", Actual: string(b), Want: contents}))
+	}
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "File", Err: e}))
+	}
+	// Retrieve a context with a timeout
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	// Defer cancel function
+	defer cancel()
+	// The test fails if Wait returns an error without waiting for the delay
+	if e := stdin.Wait(ctx); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Wait", Fn: "File", Err: e}))
+	}
+	// The test fails if the result does not report all five lines as finished
+	if res := stdin.Result(); !res.Finished || (res.Lines != 5) {
+		t.Error(tserr.Equal(&tserr.EqualArgs{Var: "Lines", Actual: int64(res.Lines), Want: 5}))
+	}
+}
+
+// TestKindFileInteractive tests that input sent dynamically is not supported as regular file. The test fails if NewStdin returns nil.
+func TestKindFileInteractive(t *testing.T) {
+	// The test fails if NewStdin returns nil for interactive input as regular file
+	if _, e := tsmock.NewStdin(tsmock.WithKind(tsmock.KindFile), tsmock.WithInteractive()); e == nil {
+		t.Error(tserr.NilFailed("SetInteractive"))
+	}
+}
+
+// TestKindDevNull tests the mocked Stdin as null device. The test fails if input is received or if any error occurs.
+func TestKindDevNull(t *testing.T) {
+	// Run a new mocked Stdin as null device
+	_, b := testKind(tsmock.KindDevNull, t)
+	// The test fails if input is received
+	if b != "" {
+		t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "input", Actual: b, Want: ""}))
+	}
+}
+
+// TestKindClosed tests the mocked Stdin as closed file. The test fails if reading os.Stdin does not return os.ErrClosed.
+func TestKindClosed(t *testing.T) {
+	// Retrieve a new mocked Stdin as closed file
+	stdin, e := tsmock.NewStdin(tsmock.WithKind(tsmock.KindClosed), tsmock.WithVisibility(false), tsmock.WithString(contents))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if the run returns an error
+	if e := stdin.Wait(context.Background()); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Wait", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if reading os.Stdin does not return os.ErrClosed
+	if _, e := os.Stdin.Read(make([]byte, 1)); !errors.Is(e, os.ErrClosed) {
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "Read", Actual: "not os.ErrClosed", Want: "os.ErrClosed"}))
+	}
+	// Restore Stdin. The test fails if Restore returns an error.
+	if e := stdin.Restore(); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Restore", Fn: "Stdin", Err: e}))
+	}
+}
+
+// TestKindOrder tests WithKind given after the option setting the input. The test fails if os.Stdin is not a regular file
+// or if any error occurs.
+func TestKindOrder(t *testing.T) {
+	// Retrieve a new mocked Stdin with the input set before the kind
+	stdin, e := tsmock.NewStdin(tsmock.WithString(contents), tsmock.WithKind(tsmock.KindFile))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "File", Err: e}))
+	}
+	// Defer restoring Stdin
+	defer stdin.Restore()
+	// Retrieve the mode of os.Stdin. The test fails if Stat returns an error.
+	fi, e := os.Stdin.Stat()
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "Stat", Fn: "File", Err: e}))
+	}
+	// The test fails if os.Stdin is not a regular file
+	if !fi.Mode().IsRegular() {
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "Stat", Actual: fi.Mode().String(), Want: "regular file"}))
+	}
+}
+
+// TestKindClosedFd0 tests KindClosed together with the installation at file descriptor 0 in both orders. The test fails if
+// NewStdin does not return an error.
+func TestKindClosedFd0(t *testing.T) {
+	// The test fails if NewStdin returns nil for KindClosed followed by Fd0
+	if _, e := tsmock.NewStdin(tsmock.WithKind(tsmock.KindClosed), tsmock.WithFd0(true)); e == nil {
+		t.Error(tserr.NilFailed("WithFd0"))
+	}
+	// The test fails if NewStdin returns nil for Fd0 followed by KindClosed
+	if _, e := tsmock.NewStdin(tsmock.WithFd0(true), tsmock.WithKind(tsmock.KindClosed)); e == nil {
+		t.Error(tserr.NilFailed("WithKind"))
+	}
+}
+
+// TestKindUnknown tests Kind to return an error for an unknown kind. The test fails if Kind returns nil.
+func TestKindUnknown(t *testing.T) {
+	// The test fails if NewStdin returns nil for an unknown kind
+	if _, e := tsmock.NewStdin(tsmock.WithKind(tsmock.KindTty + 1)); e == nil {
+		t.Error(tserr.NilFailed("WithKind"))
+	}
+}
+
+// testKind runs a new mocked Stdin of kind k with the contents and reads os.Stdin after the run. It returns the
+// mode of os.Stdin and the received input. The test fails if any error occurs.
+func testKind(k tsmock.Kind, t *testing.T) (os.FileMode, string) {
+	// Panic if t is nil
+	if t == nil {
+		panic(tserr.NilPtr())
+	}
+	// Retrieve a new mocked Stdin of kind k
+	stdin, e := tsmock.NewStdin(tsmock.WithKind(k), tsmock.WithVisibility(false), tsmock.WithString(contents))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: k.String(), Err: e}))
+	}
+	// Defer restoring Stdin. The test fails if Restore returns an error.
+	defer func() {
+		if e := stdin.Restore(); e != nil {
+			t.Error(tserr.Op(&tserr.OpArgs{Op: "Restore", Fn: k.String(), Err: e}))
+		}
+	}()
+	// Retrieve the mode of os.Stdin. The test fails if Stat returns an error.
+	fi, e := os.Stdin.Stat()
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "Stat", Fn: k.String(), Err: e}))
+	}
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: k.String(), Err: e}))
+	}
+	// Wait for the run to finish. The test fails if the run returns an error.
+	if e := stdin.Wait(context.Background()); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Wait", Fn: k.String(), Err: e}))
+	}
+	// Read the input. The test fails if ReadAll returns an error.
+	b, e := io.ReadAll(os.Stdin)
+	if e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "ReadAll", Fn: k.String(), Err: e}))
+	}
+	// Read the input of a regular file again from the start
+	if fi.Mode().IsRegular() {
+		// Seek to the start of os.Stdin. The test fails if Seek returns an error.
+		if _, e := os.Stdin.Seek(0, io.SeekStart); e != nil {
+			t.Error(tserr.Op(&tserr.OpArgs{Op: "Seek", Fn: k.String(), Err: e}))
+		}
+		// The test fails if the input read again does not equal the input read first
+		if c, _ := io.ReadAll(os.Stdin); string(c) != string(b) {
+			t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "input read again", Actual: string(c), Want: string(b)}))
+		}
+	}
+	// Return the mode of os.Stdin and the received input
+	return fi.Mode(), string(b)
+}
@@ -0,0 +1,337 @@
+// Output.go provides mocked Stdout and Stderr. The output written to os.Stdout or os.Stderr is captured into a
+// buffer, which can be read concurrently while the output is captured.
+//
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock
+
+// Import go standard library packages and tserr
+import (
+	"context" // context
+	"io"      // io
+	"os"      // os
+	"regexp"  // regexp
+	"sync"    // sync
+
+	"github.com/thorstenrie/tserr" // tserr
+)
+
+// MockOutput contains the internal state of a mocked output like Stdout or Stderr. It holds the captured file variable, pipe
+// and original file descriptors, the captured output, a channel, which is closed and renewed on each received output, and
+// an error, if any. It stores a context cancel function and a sync wait group.
+type MockOutput struct {
+	name    string              // Name of the captured file variable
+	target  **os.File           // Captured file variable, e.g., os.Stdout
+	r, w, o *os.File            // pipe and original file descriptors
+	buf     []byte              // Captured output
+	notify  chan struct{}       // Closed and renewed on each received output
+	mu      sync.Mutex          // Mutex for buf and notify
+	e       SafeVariable[error] // Error, if any
+	run     SafeVariable[bool]  // True if executing, false otherwise
+	set     SafeVariable[bool]  // True if pipe is set, false otherwise
+	stop    func() bool         // Stops restoring on context cancelation
+	gen     uint64              // Generation of the capture, incremented by Set
+	lmu     sync.Mutex          // Mutex for Set, Run and Restore
+	wg      sync.WaitGroup      // Sync wait group
+}
+
+// MockStdout is a mocked Stdout capturing the output written to os.Stdout.
+type MockStdout = MockOutput
+
+// MockStderr is a mocked Stderr capturing the output written to os.Stderr.
+type MockStderr = MockOutput
+
+// outputs holds the mocked outputs which currently replace a file variable, if any. Only one mocked
+// output at a time is allowed to replace a file variable.
+var outputs struct {
+	owner map[**os.File]*MockOutput // Mocked outputs by replaced file variable
+	mu    sync.Mutex                // Mutex
+}
+
+var (
+	// Global mocked Stdout instance
+	Stdout = NewStdout()
+	// Global mocked Stderr instance
+	Stderr = NewStderr()
+)
+
+// NewStdout returns a new mocked Stdout instance.
+func NewStdout() *MockStdout {
+	return newOutput(&os.Stdout, "os.Stdout")
+}
+
+// NewStderr returns a new mocked Stderr instance.
+func NewStderr() *MockStderr {
+	return newOutput(&os.Stderr, "os.Stderr")
+}
+
+// newOutput returns a new mocked output of the file variable target with name.
+func newOutput(target **os.File, name string) *MockOutput {
+	// Retrieve a new mocked output with a notification channel
+	out := &MockOutput{name: name, target: target, notify: make(chan struct{})}
+	// Mocked output is not executing
+	out.run.Set(false)
+	// Mocked output is not set
+	out.set.Set(false)
+	// Return the new instance
+	return out
+}
+
+// Set replaces the file variable, e.g., os.Stdout, with a pipe. Output written to the file variable is captured, after the mocked output
+// is executed with Run. Set returns an error, if the mocked output is already set or another mocked output replaces the file variable.
+func (out *MockOutput) Set() error {
+	// Lock the lifecycle mutex
+	out.lmu.Lock()
+	// Defer unlocking the lifecycle mutex
+	defer out.lmu.Unlock()
+	// Return an error if the mocked output is already set
+	if out.set.Get() {
+		return tserr.Locked(out.name)
+	}
+	// Lock the mutex
+	outputs.mu.Lock()
+	// Defer unlocking the mutex
+	defer outputs.mu.Unlock()
+	// Return an error if another mocked output replaces the file variable
+	if outputs.owner[out.target] != nil {
+		return tserr.Locked(out.name)
+	}
+	// Retrieve a new pipe
+	r, w, e := os.Pipe()
+	// Return an error if retrieving a new pipe fails
+	if e != nil {
+		return tserr.NotAvailable(&tserr.NotAvailableArgs{S: "os.Pipe", Err: e})
+	}
+	// Reset the error of a previous capture
+	out.e.Set(nil)
+	// Store the pipe and the original file and start a new generation of the capture
+	out.r, out.w, out.o = r, w, *out.target
+	out.gen++
+	// Replace the file variable with the write end of the pipe
+	*out.target = out.w
+	// Set out as the mocked output replacing the file variable
+	if outputs.owner == nil {
+		outputs.owner = make(map[**os.File]*MockOutput)
+	}
+	outputs.owner[out.target] = out
+	// Set mocked output to set
+	out.set.Set(true)
+	// Return nil
+	return nil
+}
+
+// Run starts a new go routine to capture the output into the buffer. The captured output can be read with String and Bytes while
+// the mocked output is executing. If the context is canceled, the file variable is restored as with Restore. It returns an error if the
+// mocked output is not set or already executing.
+func (out *MockOutput) Run(ctx context.Context) error {
+	// Lock the lifecycle mutex
+	out.lmu.Lock()
+	// Defer unlocking the lifecycle mutex
+	defer out.lmu.Unlock()
+	// Return an error if the mocked output is already executing
+	if out.run.Get() {
+		return tserr.Locked(out.name)
+	}
+	// Return an error if the mocked output is not set
+	if !out.set.Get() {
+		return tserr.NotSet(out.name)
+	}
+	// Add to waitgroup
+	out.wg.Add(1)
+	// Set execution to true
+	out.run.Set(true)
+	// Capture the output in a go routine
+	go out.read(out.r)
+	// Restore the file variable, if the context is canceled, unless the capture of this generation has already ended
+	gen := out.gen
+	out.stop = context.AfterFunc(ctx, func() { out.restore(gen) })
+	// Return nil
+	return nil
+}
+
+// Restore restores the original file variable, e.g., os.Stdout. It waits until all output written so far is captured and returns the
+// last occurring error, if any. The captured output remains available until Reset. Restore may be deferred to restore the original file
+// variable, even if the test panics.
+func (out *MockOutput) Restore() error {
+	// Lock the lifecycle mutex
+	out.lmu.Lock()
+	// Defer unlocking the lifecycle mutex
+	defer out.lmu.Unlock()
+	// Restore the original file variable
+	return out.restoreLocked()
+}
+
+// restore restores the original file variable on context cancelation, if the capture of generation gen is still set. A canceled
+// capture, which has already been restored and set again, is not restored.
+func (out *MockOutput) restore(gen uint64) {
+	// Lock the lifecycle mutex
+	out.lmu.Lock()
+	// Defer unlocking the lifecycle mutex
+	defer out.lmu.Unlock()
+	// Return if the capture of generation gen has already ended
+	if (out.gen != gen) || !out.set.Get() {
+		return
+	}
+	// Restore the original file variable
+	out.restoreLocked()
+}
+
+// restoreLocked restores the original file variable and returns the last occurring error, if any. The lifecycle mutex must be locked.
+func (out *MockOutput) restoreLocked() error {
+	// Lock the mutex
+	outputs.mu.Lock()
+	// Restore the file variable, if replaced by out
+	if outputs.owner[out.target] == out {
+		*out.target = out.o
+		delete(outputs.owner, out.target)
+	}
+	// Unlock the mutex
+	outputs.mu.Unlock()
+	// Stop restoring on context cancelation
+	if out.stop != nil {
+		out.stop()
+	}
+	// Close the write end of the pipe to stop capturing and store an error, if closing fails
+	if out.w != nil {
+		if e := out.w.Close(); e != nil {
+			out.e.Set(tserr.Op(&tserr.OpArgs{Op: "Close", Fn: out.name, Err: e}))
+		}
+	}
+	// Wait for the capture to read the remaining output
+	out.wg.Wait()
+	// Close the read end of the pipe and store an error, if closing fails
+	if out.r != nil {
+		if e := out.r.Close(); e != nil {
+			out.e.Set(tserr.Op(&tserr.OpArgs{Op: "Close", Fn: out.name, Err: e}))
+		}
+	}
+	// Set the file descriptors to nil
+	out.r, out.w, out.stop = nil, nil, nil
+	// Set execution to false
+	out.run.Set(false)
+	// Set mocked output to not set
+	out.set.Set(false)
+	// Return an error, if any
+	return out.e.Get()
+}
+
+// Capture captures the output of fn. It sets and runs the mocked output, executes fn and restores the original file variable,
+// even if fn panics. It returns the captured output and an error, if any.
+func (out *MockOutput) Capture(fn func()) (string, error) {
+	// Return an error if fn is nil
+	if fn == nil {
+		return "", tserr.NilPtr()
+	}
+	// Set the mocked output
+	if e := out.Set(); e != nil {
+		return "", e
+	}
+	// Defer restoring the file variable
+	defer out.Restore()
+	// Execute the mocked output
+	if e := out.Run(context.Background()); e != nil {
+		return "", e
+	}
+	// Execute fn
+	fn()
+	// Restore the file variable to capture the remaining output
+	e := out.Restore()
+	// Return the captured output and an error, if any
+	return out.String(), e
+}
+
+// Err returns the last occurring error of the current or last capture, if any. Errors occur, if reading the captured output or closing the pipe fails.
+func (out *MockOutput) Err() error {
+	// Return last occurring error, if any
+	return out.e.Get()
+}
+
+// Bytes returns a copy of the output captured so far.
+func (out *MockOutput) Bytes() []byte {
+	// Lock the mutex
+	out.mu.Lock()
+	// Defer unlocking the mutex
+	defer out.mu.Unlock()
+	// Return a copy of the captured output
+	return append([]byte(nil), out.buf...)
+}
+
+// String returns the output captured so far.
+func (out *MockOutput) String() string {
+	// Return the captured output as string
+	return string(out.Bytes())
+}
+
+// Reset discards the output captured so far.
+func (out *MockOutput) Reset() {
+	// Lock the mutex
+	out.mu.Lock()
+	// Defer unlocking the mutex
+	defer out.mu.Unlock()
+	// Discard the captured output
+	out.buf = nil
+}
+
+// read reads the output from r into the buffer until the write end of the pipe is closed. It is intended to be executed in a go routine.
+func (out *MockOutput) read(r *os.File) {
+	// Set waitgroup to done after execution finished
+	defer out.wg.Done()
+	// Buffer for reading from the pipe
+	p := make([]byte, 4096)
+	for {
+		// Read from the pipe
+		n, e := r.Read(p)
+		// Append the output to the buffer and notify waiting matches
+		if n > 0 {
+			out.mu.Lock()
+			out.buf = append(out.buf, p[:n]...)
+			close(out.notify)
+			out.notify = make(chan struct{})
+			out.mu.Unlock()
+		}
+		// Stop reading if the write end of the pipe is closed
+		if e == io.EOF {
+			return
+		}
+		// Store the error and stop reading, if reading fails
+		if e != nil {
+			out.e.Set(tserr.Op(&tserr.OpArgs{Op: "Read", Fn: out.name, Err: e}))
+			return
+		}
+	}
+}
+
+// match waits until re matches the captured output starting at position from. It returns the position of the end
+// of the match in the captured output. It returns an error if the context is done before re matches.
+func (out *MockOutput) match(ctx context.Context, re *regexp.Regexp, from int) (int, error) {
+	// Return an error if re is nil
+	if re == nil {
+		return 0, tserr.NilPtr()
+	}
+	for {
+		// Lock the mutex
+		out.mu.Lock()
+		// Limit from to the length of the captured output
+		if from > len(out.buf) {
+			from = len(out.buf)
+		}
+		// Match re against the captured output starting at from
+		loc := re.FindIndex(out.buf[from:])
+		// Retrieve the current notification channel
+		ch := out.notify
+		// Unlock the mutex
+		out.mu.Unlock()
+		// Return the end of the match, if re matches
+		if loc != nil {
+			return from + loc[1], nil
+		}
+		select {
+		// Return an error, if the context is done
+		case <-ctx.Done():
+			return from, ctx.Err()
+		// Match again, if new output has been received
+		case <-ch:
+		}
+	}
+}
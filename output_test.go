@@ -0,0 +1,212 @@
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock_test
+
+// Import go standard library packages as well as tserr and tsmock
+import (
+	"context" // context
+	"errors"  // errors
+	"fmt"     // fmt
+	"os"      // os
+	"testing" // testing
+	"time"    // time
+
+	"github.com/thorstenrie/tserr"  // tserr
+	"github.com/thorstenrie/tsmock" // tsmock
+)
+
+// TestStdout tests the mocked Stdout to capture the output written to os.Stdout. The test fails if the captured
+// output does not equal the contents, if os.Stdout is not restored or if any error occurs.
+func TestStdout(t *testing.T) {
+	// Retrieve the original os.Stdout
+	o := os.Stdout
+	// The test fails if Set returns an error
+	if e := tsmock.Stdout.Set(); e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "Set", Fn: "Stdout", Err: e}))
+	}
+	// The test fails if Run returns an error
+	if e := tsmock.Stdout.Run(context.Background()); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdout", Err: e}))
+	}
+	// Print the contents
+	fmt.Print(contents)
+	// The test fails if Restore returns an error
+	if e := tsmock.Stdout.Restore(); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Restore", Fn: "Stdout", Err: e}))
+	}
+	// The test fails if os.Stdout is not restored
+	if os.Stdout != o {
+		t.Error(tserr.NotEqual(&tserr.NotEqualArgs{X: "os.Stdout", Y: "original os.Stdout"}))
+	}
+	// The test fails if the captured output does not equal the contents
+	if out := tsmock.Stdout.String(); out != contents {
+		t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "Stdout", Want: contents, Actual: out}))
+	}
+	// Discard the captured output
+	tsmock.Stdout.Reset()
+}
+
+// TestStderrCapture tests Capture of the mocked Stderr. The test fails if the captured output does not equal the contents,
+// if os.Stderr is not restored or if any error occurs.
+func TestStderrCapture(t *testing.T) {
+	// Retrieve the original os.Stderr
+	o := os.Stderr
+	// Capture the contents printed to os.Stderr
+	out, e := tsmock.NewStderr().Capture(func() { fmt.Fprint(os.Stderr, contents) })
+	// The test fails if Capture returns an error
+	if e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Capture", Fn: "Stderr", Err: e}))
+	}
+	// The test fails if os.Stderr is not restored
+	if os.Stderr != o {
+		t.Error(tserr.NotEqual(&tserr.NotEqualArgs{X: "os.Stderr", Y: "original os.Stderr"}))
+	}
+	// The test fails if the captured output does not equal the contents
+	if out != contents {
+		t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "Stderr", Want: contents, Actual: out}))
+	}
+}
+
+// TestStdoutCapturePanic tests that Capture restores os.Stdout, if the captured function panics. The test
+// fails if os.Stdout is not restored.
+func TestStdoutCapturePanic(t *testing.T) {
+	// Retrieve the original os.Stdout
+	o := os.Stdout
+	// Capture a panicking function
+	func() {
+		// Recover from the panic
+		defer func() { recover() }()
+		tsmock.NewStdout().Capture(func() { panic("test") })
+	}()
+	// The test fails if os.Stdout is not restored
+	if os.Stdout != o {
+		t.Error(tserr.NotEqual(&tserr.NotEqualArgs{X: "os.Stdout", Y: "original os.Stdout"}))
+	}
+}
+
+// TestStdoutCancel tests that canceling the context restores os.Stdout. The test fails if os.Stdout
+// is not restored within a second.
+func TestStdoutCancel(t *testing.T) {
+	// Retrieve the original os.Stdout
+	o := os.Stdout
+	// Retrieve a new mocked Stdout
+	out := tsmock.NewStdout()
+	// Defer restoring os.Stdout
+	defer out.Restore()
+	// The test fails if Set returns an error
+	if e := out.Set(); e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "Set", Fn: "Stdout", Err: e}))
+	}
+	// Retrieve a context with a cancel function
+	ctx, cancel := context.WithCancel(context.Background())
+	// The test fails if Run returns an error
+	if e := out.Run(ctx); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdout", Err: e}))
+	}
+	// Cancel the context
+	cancel()
+	// Wait for the cancelation to restore os.Stdout. Set fails until the mocked Stdout has been restored.
+	e := out.Set()
+	for i := 0; (i < 100) && (e != nil); i++ {
+		time.Sleep(10 * time.Millisecond)
+		e = out.Set()
+	}
+	// The test fails if the mocked Stdout is not restored within a second
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "Set", Fn: "Stdout", Err: e}))
+	}
+	// Restore os.Stdout. The test fails if os.Stdout is not the original os.Stdout.
+	out.Restore()
+	if os.Stdout != o {
+		t.Error(tserr.NotEqual(&tserr.NotEqualArgs{X: "os.Stdout", Y: "original os.Stdout"}))
+	}
+}
+
+// TestStdoutCancelStale tests that a canceled capture, which has been restored and set again, is not restored by the
+// cancelation. The test fails if output written after the capture has been set again is not captured.
+func TestStdoutCancelStale(t *testing.T) {
+	// Retrieve a new mocked Stdout
+	out := tsmock.NewStdout()
+	// Defer restoring os.Stdout
+	defer out.Restore()
+	// The test fails if Set returns an error
+	if e := out.Set(); e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "Set", Fn: "Stdout", Err: e}))
+	}
+	// Retrieve a context with a cancel function
+	ctx, cancel := context.WithCancel(context.Background())
+	// The test fails if Run returns an error
+	if e := out.Run(ctx); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdout", Err: e}))
+	}
+	// Cancel the context and restore, set and run the mocked Stdout again, before the cancelation may be executed
+	cancel()
+	out.Restore()
+	// The test fails if Set or Run return an error
+	if e := errors.Join(out.Set(), out.Run(context.Background())); e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "Set", Fn: "Stdout", Err: e}))
+	}
+	// Give the cancelation time to be executed
+	time.Sleep(20 * time.Millisecond)
+	// Write to os.Stdout
+	fmt.Print("Aragorn")
+	// Restore os.Stdout. The test fails if Restore returns an error.
+	if e := out.Restore(); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Restore", Fn: "Stdout", Err: e}))
+	}
+	// The test fails if the output has not been captured
+	if out.String() != "Aragorn" {
+		t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "output", Actual: out.String(), Want: "Aragorn"}))
+	}
+}
+
+// TestStdoutLocked tests that Set returns an error if another mocked Stdout replaces os.Stdout and that Run
+// returns an error if the mocked Stdout is not set. The test fails if Set or Run return nil.
+func TestStdoutLocked(t *testing.T) {
+	// The test fails if Run returns nil without Set
+	if e := tsmock.NewStdout().Run(context.Background()); e == nil {
+		t.Error(tserr.NilFailed("Run"))
+	}
+	// The test fails if Set returns an error
+	if e := tsmock.Stdout.Set(); e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "Set", Fn: "Stdout", Err: e}))
+	}
+	// Defer restoring os.Stdout
+	defer tsmock.Stdout.Restore()
+	// The test fails if Set of another mocked Stdout returns nil
+	if e := tsmock.NewStdout().Set(); e == nil {
+		t.Error(tserr.NilFailed("Set"))
+	}
+}
+
+// TestStdoutErr tests that Restore and Err report an error, if the program closed the captured os.Stdout. The test fails if
+// Restore or Err return nil or if Err returns an error after the mocked Stdout has been set again.
+func TestStdoutErr(t *testing.T) {
+	// Retrieve a new mocked Stdout
+	out := tsmock.NewStdout()
+	// The test fails if Set or Run return an error
+	if e := errors.Join(out.Set(), out.Run(context.Background())); e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "Set", Fn: "Stdout", Err: e}))
+	}
+	// Close the captured os.Stdout
+	os.Stdout.Close()
+	// The test fails if Restore returns nil
+	if e := out.Restore(); e == nil {
+		t.Error(tserr.NilFailed("Restore"))
+	}
+	// The test fails if Err returns nil
+	if e := out.Err(); e == nil {
+		t.Error(tserr.NilFailed("Err"))
+	}
+	// The test fails if Set returns an error
+	if e := out.Set(); e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "Set", Fn: "Stdout", Err: e}))
+	}
+	// Defer restoring os.Stdout
+	defer out.Restore()
+	// The test fails if Err returns the error of the previous capture
+	if e := out.Err(); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Err", Fn: "Stdout", Err: e}))
+	}
+}
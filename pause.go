@@ -0,0 +1,103 @@
+// Pause.go provides pausing a running mocked Stdin between lines. A paused mocked Stdin waits before writing the next line
+// until it is resumed or stepped, so that a test can inspect the state of the program or interleave its own assertions
+// between scripted lines. Pause and Step may also be called before the run to start paused.
+//
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock
+
+// Import go standard library package context and tserr
+import (
+	"context" // context
+
+	"github.com/thorstenrie/tserr" // tserr
+)
+
+// pacing holds the state of pausing the mocked Stdin.
+type pacing struct {
+	paused bool          // True if paused
+	steps  int           // Number of lines to be written while paused
+	wake   chan struct{} // Closed and replaced on each change to wake a waiting run
+}
+
+// Pause pauses the mocked Stdin. The run finishes writing the current line and waits before writing the next line until Resume or Step
+// is called. Lines are the parts written into the mocked Stdin, which are chunks in raw mode with chunks. Directives are executed while
+// paused. The mocked Stdin stays paused for following runs until Resume is called. Pause is safe to call from any go routine.
+func (stdin *MockStdin) Pause() {
+	// Pause and wake a waiting run
+	stdin.pace.Update(func(p pacing) (pacing, error) {
+		p.paused = true
+		return p.changed(), nil
+	})
+}
+
+// Resume continues writing all lines of the paused mocked Stdin. Resume is safe to call from any go routine.
+func (stdin *MockStdin) Resume() {
+	// Resume and wake a waiting run
+	stdin.pace.Update(func(p pacing) (pacing, error) {
+		p.paused, p.steps = false, 0
+		return p.changed(), nil
+	})
+}
+
+// Step writes the next n lines and pauses the mocked Stdin again. If the mocked Stdin is not paused, it is paused after the next n lines.
+// Steps add up, if Step is called again before the lines have been written. Step is safe to call from any go routine. It returns an error
+// if n is lower than 1.
+func (stdin *MockStdin) Step(n int) error {
+	// Return an error if n is lower than 1
+	if n < 1 {
+		return tserr.Higher(&tserr.HigherArgs{Var: "n", Actual: int64(n), LowerBound: 1})
+	}
+	// Pause after n more lines and wake a waiting run
+	return stdin.pace.Update(func(p pacing) (pacing, error) {
+		p.paused, p.steps = true, p.steps+n
+		return p.changed(), nil
+	})
+}
+
+// changed wakes a waiting run and returns p with a new channel to wake the next waiting run.
+func (p pacing) changed() pacing {
+	// Wake a waiting run
+	if p.wake != nil {
+		close(p.wake)
+	}
+	// Retrieve a new channel
+	p.wake = make(chan struct{})
+	// Return p
+	return p
+}
+
+// paced waits until the next line may be written. It returns immediately, if the mocked Stdin is not paused, and takes a step,
+// if paused with steps left. It returns false, if the context is canceled while waiting, and true otherwise.
+func (stdin *MockStdin) paced(ctx context.Context) bool {
+	for {
+		// True if the next line may be written
+		var ok bool
+		// Channel closed on the next change
+		var wake chan struct{}
+		// Take a step, if paused with steps left, and retrieve the channel for the next change otherwise
+		stdin.pace.Update(func(p pacing) (pacing, error) {
+			switch {
+			case !p.paused:
+				ok = true
+			case p.steps > 0:
+				p.steps, ok = p.steps-1, true
+			case p.wake == nil:
+				p.wake = make(chan struct{})
+			}
+			wake = p.wake
+			return p, nil
+		})
+		// Return true, if the next line may be written
+		if ok {
+			return true
+		}
+		// Wait for the next change or the cancellation of the context
+		select {
+		case <-wake:
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
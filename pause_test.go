@@ -0,0 +1,141 @@
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock_test
+
+// Import go standard library packages as well as tserr and tsmock
+import (
+	"bufio"   // bufio
+	"context" // context
+	"os"      // os
+	"testing" // testing
+	"time"    // time
+
+	"github.com/thorstenrie/tserr"  // tserr
+	"github.com/thorstenrie/tsmock" // tsmock
+)
+
+// TestPause tests pausing, stepping and resuming the mocked Stdin. The test fails if a line is written while paused, if Step
+// does not write exactly the next line, if Resume does not write the remaining lines or if any error occurs.
+func TestPause(t *testing.T) {
+	// Retrieve a new mocked Stdin
+	stdin, e := tsmock.NewStdin(tsmock.WithVisibility(false), tsmock.WithString("Aragorn\nBoromir\nGandalf\n"))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Defer restoring Stdin
+	defer stdin.Restore()
+	// Start paused
+	stdin.Pause()
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Read the lines from os.Stdin in a go routine
+	lines := make(chan string)
+	go func() {
+		s := bufio.NewScanner(os.Stdin)
+		for s.Scan() {
+			lines <- s.Text()
+		}
+		close(lines)
+	}()
+	// The test fails if a line is written while paused
+	testPauseNone(lines, t)
+	// Write the next line. The test fails if Step returns an error.
+	if e := stdin.Step(1); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Step", Fn: "1", Err: e}))
+	}
+	// The test fails if the next line is not written
+	testPauseLine(lines, "Aragorn", t)
+	// The test fails if another line is written after the step
+	testPauseNone(lines, t)
+	// Resume writing
+	stdin.Resume()
+	// The test fails if the remaining lines are not written
+	testPauseLine(lines, "Boromir", t)
+	testPauseLine(lines, "Gandalf", t)
+	// The test fails if the run returns an error
+	if e := stdin.Wait(context.Background()); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Wait", Fn: "Stdin", Err: e}))
+	}
+}
+
+// TestPauseRestore tests Restore to stop a paused mocked Stdin. The test fails if Restore returns an error or if the run
+// is not canceled.
+func TestPauseRestore(t *testing.T) {
+	// Retrieve a new mocked Stdin paused after the first line
+	stdin, e := tsmock.NewStdin(tsmock.WithVisibility(false), tsmock.WithString(contents))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Pause after the first line. The test fails if Step returns an error.
+	if e := stdin.Step(1); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Step", Fn: "1", Err: e}))
+	}
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Read the first line. The test fails if the line does not equal the first line of the contents.
+	testSendRead(bufio.NewReader(os.Stdin), "Aragorn\n", t)
+	// Restore Stdin. The test fails if Restore returns an error.
+	if e := stdin.Restore(); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Restore", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if the run is not canceled
+	if res := stdin.Result(); !res.Cancelled || (res.Lines != 1) {
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "Result", Actual: "not canceled after one line", Want: "canceled after one line"}))
+	}
+}
+
+// TestStepZero tests Step to return an error, if n is lower than 1. The test fails if Step returns nil.
+func TestStepZero(t *testing.T) {
+	// Retrieve a new mocked Stdin
+	stdin, e := tsmock.NewStdin()
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if Step returns nil
+	if e := stdin.Step(0); e == nil {
+		t.Error(tserr.NilFailed("Step"))
+	}
+}
+
+// testPauseLine receives the next line from lines. The test fails if the line does not equal want or if no line is received within a second.
+func testPauseLine(lines <-chan string, want string, t *testing.T) {
+	// Panic if t is nil
+	if t == nil {
+		panic(tserr.NilPtr())
+	}
+	select {
+	// The test fails if the line does not equal want
+	case l := <-lines:
+		if l != want {
+			t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "line", Actual: l, Want: want}))
+		}
+	// The test fails if no line is received within a second
+	case <-time.After(time.Second):
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "receive line", Actual: "timeout", Want: want}))
+	}
+}
+
+// testPauseNone waits briefly for a line from lines. The test fails if a line is received.
+func testPauseNone(lines <-chan string, t *testing.T) {
+	// Panic if t is nil
+	if t == nil {
+		panic(tserr.NilPtr())
+	}
+	select {
+	// The test fails if a line is received
+	case l := <-lines:
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "receive line", Actual: l, Want: "no line while paused"}))
+	// Return if no line is received
+	case <-time.After(20 * time.Millisecond):
+	}
+}
@@ -0,0 +1,91 @@
+// Pty.go provides a pseudo-terminal for the mocked Stdin, so that the program under test sees a terminal as os.Stdin.
+// The input is written to the master side and the slave side replaces os.Stdin. Visibility is mapped onto the
+// ECHO flag of the terminal. Pseudo-terminals are only supported on Linux.
+//
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock
+
+// Import go standard library package os
+import (
+	"os" // os
+)
+
+// ctrlD is the end-of-file character of a terminal
+const ctrlD byte = 0x04
+
+// Pty enables a pseudo-terminal instead of a pipe for the mocked Stdin, if p is true. The pseudo-terminal is opened by the
+// next call of Set or any other input setter. Pty(true) is the same as Kind(KindTty). Pty(false) resets the kind to a pipe,
+// if a pseudo-terminal is set. It returns an error if the mocked Stdin is executing.
+func (stdin *MockStdin) Pty(p bool) error {
+	// Return an error if mocked Stdin is executing
+	if e := stdin.notRunning(); e != nil {
+		return e
+	}
+	// Set the kind to a pseudo-terminal, if p is true, or reset a pseudo-terminal to a pipe otherwise
+	return stdin.kind.Update(func(k Kind) (Kind, error) {
+		if p {
+			return KindTty, nil
+		}
+		if k == KindTty {
+			return KindPipe, nil
+		}
+		return k, nil
+	})
+}
+
+// WithPty returns an option to enable a pseudo-terminal for the mocked Stdin, if p is true. See Pty.
+func WithPty(p bool) Option {
+	return func(stdin *MockStdin) error {
+		return stdin.Pty(p)
+	}
+}
+
+// openTerminal opens a new pseudo-terminal. The master side is set as write end and the slave side as read end
+// of the mocked Stdin. The ECHO flag is set to the visibility. It starts a go routine to write the echo
+// of the terminal to the echo writer. It returns an error, if opening the pseudo-terminal fails.
+func (stdin *MockStdin) openTerminal() error {
+	// Open a new pseudo-terminal
+	m, s, e := openPty()
+	// Return an error if opening the pseudo-terminal fails
+	if e != nil {
+		return e
+	}
+	// Set the ECHO flag to the visibility
+	if e := setEcho(s, stdin.v.Get()); e != nil {
+		m.Close()
+		s.Close()
+		return e
+	}
+	// Set the master side as write end and the slave side as read end
+	stdin.w, stdin.r, stdin.tty = m, s, true
+	// Add to waitgroup for the echo
+	stdin.ewg.Add(1)
+	// Write the echo of the terminal in a go routine
+	go stdin.echoTerminal(m)
+	// Return nil
+	return nil
+}
+
+// echoTerminal reads the echo of the terminal from the master side m and writes it to the echo writer until m is closed.
+// It is intended to be executed in a go routine.
+func (stdin *MockStdin) echoTerminal(m *os.File) {
+	// Set waitgroup to done after execution finished
+	defer stdin.ewg.Done()
+	// Buffer for reading from the master side
+	p := make([]byte, 4096)
+	for {
+		// Read the echo from the master side
+		n, e := m.Read(p)
+		// Write the echo to the echo writer
+		if n > 0 {
+			stdin.print(string(p[:n]))
+		}
+		// Write the echo of an incomplete last line and stop reading, if the master side is closed
+		if e != nil {
+			stdin.flushEcho()
+			return
+		}
+	}
+}
@@ -0,0 +1,115 @@
+//go:build linux
+
+// Pty_linux.go provides pseudo-terminals on Linux for the mocked Stdin. A pseudo-terminal is opened with /dev/ptmx.
+// The echo of the input is controlled with the ECHO flag of the terminal. The bytes left in the buffer of a pipe
+// or a terminal are retrieved with TIOCINQ.
+//
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock
+
+// Import go standard library packages and tserr
+import (
+	"os"      // os
+	"strconv" // strconv
+	"syscall" // syscall
+	"unsafe"  // unsafe
+
+	"github.com/thorstenrie/tserr" // tserr
+)
+
+// openPty opens a new pseudo-terminal. It returns the master and the slave side of the pseudo-terminal. It returns
+// an error, if opening the pseudo-terminal fails.
+func openPty() (*os.File, *os.File, error) {
+	// Open the master side of a new pseudo-terminal
+	m, e := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	// Return an error if opening the master side fails
+	if e != nil {
+		return nil, nil, tserr.NotAvailable(&tserr.NotAvailableArgs{S: "/dev/ptmx", Err: e})
+	}
+	// Unlock the slave side
+	var u int32 = 0
+	if e := ioctl(m, syscall.TIOCSPTLCK, unsafe.Pointer(&u)); e != nil {
+		m.Close()
+		return nil, nil, tserr.Op(&tserr.OpArgs{Op: "unlock", Fn: "/dev/ptmx", Err: e})
+	}
+	// Retrieve the number of the slave side
+	var n uint32
+	if e := ioctl(m, syscall.TIOCGPTN, unsafe.Pointer(&n)); e != nil {
+		m.Close()
+		return nil, nil, tserr.Op(&tserr.OpArgs{Op: "get number", Fn: "/dev/ptmx", Err: e})
+	}
+	// Name of the slave side
+	fn := "/dev/pts/" + strconv.FormatUint(uint64(n), 10)
+	// Open the slave side
+	s, e := os.OpenFile(fn, os.O_RDWR|syscall.O_NOCTTY, 0)
+	// Return an error if opening the slave side fails
+	if e != nil {
+		m.Close()
+		return nil, nil, tserr.NotAvailable(&tserr.NotAvailableArgs{S: fn, Err: e})
+	}
+	// Return the master and the slave side
+	return m, s, nil
+}
+
+// setEcho sets the ECHO flag of the terminal f to echo. It returns an error, if retrieving or setting the terminal attributes fails.
+func setEcho(f *os.File, echo bool) error {
+	// Retrieve the terminal attributes
+	var t syscall.Termios
+	if e := ioctl(f, syscall.TCGETS, unsafe.Pointer(&t)); e != nil {
+		return tserr.Op(&tserr.OpArgs{Op: "get attributes", Fn: f.Name(), Err: e})
+	}
+	// Set or clear the ECHO flag
+	if echo {
+		t.Lflag |= syscall.ECHO
+	} else {
+		t.Lflag &^= syscall.ECHO
+	}
+	// Set the terminal attributes
+	if e := ioctl(f, syscall.TCSETS, unsafe.Pointer(&t)); e != nil {
+		return tserr.Op(&tserr.OpArgs{Op: "set attributes", Fn: f.Name(), Err: e})
+	}
+	// Return nil
+	return nil
+}
+
+// buffered returns the number of bytes left unread in the buffer of the pipe or terminal f. It returns an error, if
+// retrieving the number fails.
+func buffered(f *os.File) (int64, error) {
+	// Return an error if f is nil
+	if f == nil {
+		return 0, tserr.NilPtr()
+	}
+	// Retrieve the number of unread bytes
+	var n int32
+	if e := ioctl(f, syscall.TIOCINQ, unsafe.Pointer(&n)); e != nil {
+		return 0, tserr.Op(&tserr.OpArgs{Op: "get unread bytes", Fn: f.Name(), Err: e})
+	}
+	// Return the number of unread bytes
+	return int64(n), nil
+}
+
+// ioctl executes the ioctl system call with request req and argument arg on file f without changing the blocking mode of f.
+func ioctl(f *os.File, req uintptr, arg unsafe.Pointer) error {
+	// Retrieve the raw connection of f
+	c, e := f.SyscallConn()
+	// Return an error if retrieving the raw connection fails
+	if e != nil {
+		return e
+	}
+	// Error of the system call
+	var errno syscall.Errno
+	// Execute the system call on the file descriptor
+	if e := c.Control(func(fd uintptr) {
+		_, _, errno = syscall.Syscall(syscall.SYS_IOCTL, fd, req, uintptr(arg))
+	}); e != nil {
+		return e
+	}
+	// Return an error if the system call fails
+	if errno != 0 {
+		return errno
+	}
+	// Return nil
+	return nil
+}
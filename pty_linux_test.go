@@ -0,0 +1,123 @@
+//go:build linux
+
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock_test
+
+// Import go standard library packages as well as tserr and tsmock
+import (
+	"bufio"   // bufio
+	"bytes"   // bytes
+	"context" // context
+	"os"      // os
+	"testing" // testing
+
+	"github.com/thorstenrie/tserr"  // tserr
+	"github.com/thorstenrie/tsmock" // tsmock
+)
+
+// TestPty tests the mocked Stdin with a pseudo-terminal and visibility set to false. The test fails if os.Stdin is not
+// a character device, if the received input does not equal the contents, if the input is echoed or if any error occurs.
+func TestPty(t *testing.T) {
+	// Run the mocked Stdin with a pseudo-terminal and compare the received input and echo
+	testPty(false, t)
+}
+
+// TestPtyEcho tests the mocked Stdin with a pseudo-terminal and visibility set to true. The test fails if os.Stdin is not
+// a character device, if the received input or its echo do not equal the contents or if any error occurs.
+func TestPtyEcho(t *testing.T) {
+	// Run the mocked Stdin with a pseudo-terminal and compare the received input and echo
+	testPty(true, t)
+}
+
+// TestPtyKeys tests the key notation with a pseudo-terminal. The terminal erases the character before <BS> and ends the
+// line with <Enter>. The test fails if the line received from os.Stdin does not equal the edited line.
+func TestPtyKeys(t *testing.T) {
+	// Retrieve a new mocked Stdin with a pseudo-terminal and the key notation in raw mode
+	stdin, e := tsmock.NewStdin(tsmock.WithPty(true), tsmock.WithVisibility(false), tsmock.WithRaw(true), tsmock.WithKeyNotation(true), tsmock.WithString("Gandalg<BS>f<Enter>"))
+	// Skip the test, if a pseudo-terminal is not available
+	if e != nil {
+		t.Skip(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "pseudo-terminal", Err: e}))
+	}
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Read the edited line. The test fails if ReadString returns an error.
+	l, e := bufio.NewReader(os.Stdin).ReadString('\n')
+	if e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "ReadString", Fn: "Stdin", Err: e}))
+	}
+	// Restore Stdin. The test fails if Restore returns an error.
+	if e := stdin.Restore(); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Restore", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if the line does not equal the edited line
+	if l != "Gandalf\n" {
+		t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "line", Actual: l, Want: "Gandalf\n"}))
+	}
+}
+
+// testPty runs a mocked Stdin with a pseudo-terminal and visibility set to v. It compares the input received from os.Stdin
+// and the echo of the terminal with the contents. The test is skipped, if a pseudo-terminal is not available.
+func testPty(v bool, t *testing.T) {
+	// Panic if t is nil
+	if t == nil {
+		panic(tserr.NilPtr())
+	}
+	// Buffer for the echo of the input
+	var echo bytes.Buffer
+	// Retrieve a new mocked Stdin with a pseudo-terminal
+	stdin, e := tsmock.NewStdin(tsmock.WithPty(true), tsmock.WithVisibility(v), tsmock.WithEcho(&echo), tsmock.WithString(contents))
+	// Skip the test, if a pseudo-terminal is not available
+	if e != nil {
+		t.Skip(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "pseudo-terminal", Err: e}))
+	}
+	// Retrieve file info of os.Stdin
+	fi, e := os.Stdin.Stat()
+	// The test fails if Stat returns an error
+	if e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Stat", Fn: "os.Stdin", Err: e}))
+	} else if fi.Mode()&os.ModeCharDevice == 0 {
+		// The test fails if os.Stdin is not a character device
+		t.Error(tserr.NotEqual(&tserr.NotEqualArgs{X: "os.Stdin", Y: "character device"}))
+	}
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Scan stdin and compare the retrieved text with the contents
+	if e := testStdinEval(contents, t); e != nil {
+		t.Error(e)
+	}
+	// Restore Stdin
+	if e := stdin.Restore(); e != nil {
+		// The test fails if Restore returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Restore", Fn: "Stdin", Err: e}))
+	}
+	// Expected echo of the terminal
+	want := ""
+	if v {
+		want = contents
+	}
+	// The test fails if the echo does not equal the expected echo
+	if got := echo.String(); got != want {
+		t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "echo", Want: want, Actual: got}))
+	}
+}
+
+// TestPtyEchoStyle tests the styled echo of a pseudo-terminal with typing character by character. The test fails if the echo is not
+// prefixed once per line or if the line endings of the terminal are not normalized.
+func TestPtyEchoStyle(t *testing.T) {
+	// Retrieve a typing model with a short delay
+	m, e := tsmock.NewConstantTyping(10000)
+	// The test fails if NewConstantTyping returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewConstantTyping", Fn: "10000", Err: e}))
+	}
+	// The test fails if the echo is not prefixed once per line with normalized line endings
+	testEcho("> Aragorn\n> Gé\n", t, tsmock.WithPty(true), tsmock.WithTyping(m), tsmock.WithEchoPrefix("> "))
+}
@@ -0,0 +1,31 @@
+//go:build !linux
+
+// Pty_other.go provides a fallback for platforms without support of pseudo-terminals for the mocked Stdin. The bytes
+// left in the buffer of a pipe are not reported on these platforms.
+//
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock
+
+// Import go standard library package os and tserr
+import (
+	"os" // os
+
+	"github.com/thorstenrie/tserr" // tserr
+)
+
+// openPty returns an error, since pseudo-terminals are not supported on this platform.
+func openPty() (*os.File, *os.File, error) {
+	return nil, nil, tserr.NotAvailable(&tserr.NotAvailableArgs{S: "pseudo-terminal", Err: tserr.Forbidden("platform")})
+}
+
+// setEcho returns an error, since pseudo-terminals are not supported on this platform.
+func setEcho(f *os.File, echo bool) error {
+	return tserr.NotAvailable(&tserr.NotAvailableArgs{S: "pseudo-terminal", Err: tserr.Forbidden("platform")})
+}
+
+// buffered returns an error, since retrieving the bytes left in the buffer of a pipe is not supported on this platform.
+func buffered(f *os.File) (int64, error) {
+	return 0, tserr.NotAvailable(&tserr.NotAvailableArgs{S: "TIOCINQ", Err: tserr.Forbidden("platform")})
+}
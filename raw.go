@@ -0,0 +1,161 @@
+// Raw.go provides the raw input mode of the mocked Stdin. In raw mode, the input is passed through byte for byte,
+// either in chunks of a configured size or at natural line boundaries. Line endings and a missing final newline
+// are preserved. Otherwise, the input is read line by line and each line is terminated with a newline.
+//
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock
+
+// Import go standard library packages and tserr
+import (
+	"bufio" // bufio
+	"bytes" // bytes
+	"io"    // io
+
+	"github.com/thorstenrie/tserr" // tserr
+)
+
+// Raw enables the raw input mode of the mocked Stdin, if r is true. In raw mode, the input is passed through byte for byte. It is
+// written in chunks of the size set with Chunk or, if the chunk size is zero, at natural line boundaries with the line endings preserved.
+// The delay is applied to each chunk or line. Raw returns an error if the mocked Stdin is executing.
+func (stdin *MockStdin) Raw(r bool) error {
+	// Return an error if mocked Stdin is executing
+	if e := stdin.notRunning(); e != nil {
+		return e
+	}
+	// Set raw input mode to r
+	stdin.raw.Set(r)
+	// Return nil
+	return nil
+}
+
+// Chunk sets the size of the chunks written in raw input mode to n bytes. If n is zero, the input is written at natural line boundaries,
+// which is the default. Chunk returns an error if n is negative or if the mocked Stdin is executing.
+func (stdin *MockStdin) Chunk(n int) error {
+	// Return an error if n is negative
+	if n < 0 {
+		return tserr.Higher(&tserr.HigherArgs{Var: "n", Actual: int64(n), LowerBound: 0})
+	}
+	// Return an error if mocked Stdin is executing
+	if e := stdin.notRunning(); e != nil {
+		return e
+	}
+	// Set chunk size to n
+	stdin.chunk.Set(n)
+	// Return nil
+	return nil
+}
+
+// WithRaw returns an option to enable the raw input mode of the mocked Stdin, if r is true. See Raw.
+func WithRaw(r bool) Option {
+	return func(stdin *MockStdin) error {
+		return stdin.Raw(r)
+	}
+}
+
+// WithChunk returns an option to set the size of the chunks written in raw input mode to n bytes. See Chunk.
+func WithChunk(n int) Option {
+	return func(stdin *MockStdin) error {
+		return stdin.Chunk(n)
+	}
+}
+
+// split returns a function, which returns the next part of the input on each call. At the end of the input,
+// the function returns io.EOF. If reading the input fails, the function returns the error.
+func (stdin *MockStdin) split() func() ([]byte, error) {
+	// Retrieve the input
+	var in io.Reader = stdin.in
+	// Translate the key notation in the input, if enabled
+	if stdin.keys.Get() {
+		in = newKeyReader(in)
+	}
+	// Return a function returning lines terminated with a newline, if raw input mode is disabled
+	if !stdin.raw.Get() {
+		return scanLines(in)
+	}
+	// Return a function returning the input available, if the input is sent dynamically
+	if _, ok := stdin.in.(stopper); ok {
+		return rawAvailable(in, stdin.chunk.Get())
+	}
+	// Return a function returning chunks of the chunk size, if the chunk size is not zero
+	if n := stdin.chunk.Get(); n > 0 {
+		return rawChunks(in, n)
+	}
+	// Return a function returning lines with preserved line endings
+	return rawLines(in)
+}
+
+// scanLines returns a function, which returns the next line of in terminated with a newline on each call.
+// Line endings are normalized and lines longer than the maximum token size of bufio.Scanner return an error.
+func scanLines(in io.Reader) func() ([]byte, error) {
+	// Retrieve a scanner on in
+	s := bufio.NewScanner(in)
+	return func() ([]byte, error) {
+		// Return a copy of the next line and a newline
+		if s.Scan() {
+			return append(append([]byte(nil), s.Bytes()...), '\n'), nil
+		}
+		// Return the error of the scanner, if any
+		if e := s.Err(); e != nil {
+			return nil, e
+		}
+		// Return end of input
+		return nil, io.EOF
+	}
+}
+
+// rawLines returns a function, which returns the next line of in with its line ending preserved on each call.
+// The last line is returned without a newline, if the input does not end with a newline.
+func rawLines(in io.Reader) func() ([]byte, error) {
+	// Retrieve a buffered reader on in
+	r := bufio.NewReader(in)
+	return func() ([]byte, error) {
+		// Return the next line including the newline
+		return r.ReadBytes('\n')
+	}
+}
+
+// rawChunks returns a function, which returns the next chunk of in with size n on each call. The last chunk
+// may be shorter than n.
+func rawChunks(in io.Reader, n int) func() ([]byte, error) {
+	// Buffer for the chunk
+	p := make([]byte, n)
+	return func() ([]byte, error) {
+		// Read the next chunk
+		m, e := io.ReadFull(in, p)
+		// Return end of input for a shorter last chunk
+		if e == io.ErrUnexpectedEOF {
+			e = io.EOF
+		}
+		// Return the chunk
+		return p[:m], e
+	}
+}
+
+// rawAvailable returns a function, which returns the input of in available without waiting for a newline or a full chunk on
+// each call. It only waits, if no input is available. The input is split after each newline, if n is zero, and into chunks
+// of at most n bytes otherwise.
+func rawAvailable(in io.Reader, n int) func() ([]byte, error) {
+	// Retrieve a buffered reader on in
+	r := bufio.NewReader(in)
+	return func() ([]byte, error) {
+		// Wait for input, if no input is buffered
+		if _, e := r.Peek(1); e != nil {
+			return nil, e
+		}
+		// Retrieve the input buffered
+		p, _ := r.Peek(r.Buffered())
+		// Limit the input to the first line or to the chunk size
+		if i := bytes.IndexByte(p, '\n'); (n == 0) && (i >= 0) {
+			p = p[:i+1]
+		} else if (n > 0) && (len(p) > n) {
+			p = p[:n]
+		}
+		// Copy the input and discard it from the buffered reader
+		c := append([]byte(nil), p...)
+		r.Discard(len(c))
+		// Return the input
+		return c, nil
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock_test
+
+// Import go standard library packages as well as tserr and tsmock
+import (
+	"bytes"   // bytes
+	"context" // context
+	"io"      // io
+	"os"      // os
+	"strings" // strings
+	"testing" // testing
+
+	"github.com/thorstenrie/tserr"  // tserr
+	"github.com/thorstenrie/tsmock" // tsmock
+)
+
+// rawContents contains CRLF line endings, binary data and no final newline
+var rawContents = "Aragorn\r\nBoromir\r\n\x00\x01\xff\nGandalf"
+
+// TestRawLines tests the raw input mode at natural line boundaries. The test fails if the input received from
+// os.Stdin does not equal the input byte for byte or if any error occurs.
+func TestRawLines(t *testing.T) {
+	// Run the mocked Stdin in raw input mode at natural line boundaries
+	testRaw(rawContents, 0, t)
+}
+
+// TestRawChunks tests the raw input mode with chunks of three bytes. The test fails if the input received from
+// os.Stdin does not equal the input byte for byte or if any error occurs.
+func TestRawChunks(t *testing.T) {
+	// Run the mocked Stdin in raw input mode with chunks of three bytes
+	testRaw(rawContents, 3, t)
+}
+
+// TestRawLongLine tests the raw input mode with a single line longer than the maximum token size of bufio.Scanner.
+// The test fails if the input received from os.Stdin does not equal the input byte for byte or if any error occurs.
+func TestRawLongLine(t *testing.T) {
+	// Run the mocked Stdin in raw input mode with a single line of 100 KiB
+	testRaw(strings.Repeat("x", 100*1024), 0, t)
+}
+
+// TestLongLineErr tests that a line longer than the maximum token size of bufio.Scanner results in an error
+// reported by Err, if the raw input mode is disabled. The test fails if Err returns nil.
+func TestLongLineErr(t *testing.T) {
+	// Retrieve a new mocked Stdin with a single line of 100 KiB as input
+	stdin, e := tsmock.NewStdin(tsmock.WithVisibility(false), tsmock.WithString(strings.Repeat("x", 100*1024)))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Read all input
+	io.ReadAll(os.Stdin)
+	// The test fails if Restore returns nil
+	if e := stdin.Restore(); e == nil {
+		t.Error(tserr.NilFailed("Restore"))
+	}
+	// The test fails if Err returns nil
+	if e := stdin.Err(); e == nil {
+		t.Error(tserr.NilFailed("Err"))
+	}
+}
+
+// TestChunkNegative tests if Chunk returns an error in case of a negative value. The test
+// fails if Chunk returns nil.
+func TestChunkNegative(t *testing.T) {
+	if e := tsmock.Stdin.Chunk(-1); e == nil {
+		t.Error(tserr.NilFailed("Chunk"))
+	}
+}
+
+// testRaw runs a mocked Stdin in raw input mode with chunk size n and input in. The test fails if the
+// input received from os.Stdin does not equal in byte for byte or if any error occurs.
+func testRaw(in string, n int, t *testing.T) {
+	// Panic if t is nil
+	if t == nil {
+		panic(tserr.NilPtr())
+	}
+	// Buffer for the echo of the input
+	var echo bytes.Buffer
+	// Retrieve a new mocked Stdin in raw input mode
+	stdin, e := tsmock.NewStdin(tsmock.WithRaw(true), tsmock.WithChunk(n), tsmock.WithEcho(&echo), tsmock.WithString(in))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Read all input from os.Stdin
+	b, e := io.ReadAll(os.Stdin)
+	// The test fails if ReadAll returns an error
+	if e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "ReadAll", Fn: "os.Stdin", Err: e}))
+	}
+	// Restore Stdin
+	if e := stdin.Restore(); e != nil {
+		// The test fails if Restore returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Restore", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if the received input does not equal in
+	if string(b) != in {
+		t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "os.Stdin", Want: in, Actual: string(b)}))
+	}
+	// The test fails if the echo does not equal in
+	if echo.String() != in {
+		t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "echo", Want: in, Actual: echo.String()}))
+	}
+}
@@ -1,5 +1,5 @@
 // Safe_var.go provides thread-safe variables of any type. The value of the variable is retrieved by Get.
-// The value of the variable is set with Set.
+// The value of the variable is set with Set or updated atomically from its current value with Update.
 //
 // Version v1.0
 // Date 13 Aug 2023
@@ -47,3 +47,22 @@ func (inst *SafeVariable[T]) Set(v T) {
 	// Set the value to v
 	inst.v = v
 }
+
+// Update calls fn with the value of the thread-safe variable and sets the value to the value returned by fn.
+// The mutex is locked while fn is executed. If fn returns an error, the value is left unchanged and the error is returned.
+func (inst *SafeVariable[T]) Update(fn func(T) (T, error)) error {
+	// Lock the mutex
+	inst.mu.Lock()
+	// Defer unlocking the mutex
+	defer inst.mu.Unlock()
+	// Retrieve the new value from fn
+	v, e := fn(inst.v)
+	// Return the error, if fn fails
+	if e != nil {
+		return e
+	}
+	// Set the value to v
+	inst.v = v
+	// Return nil
+	return nil
+}
@@ -0,0 +1,272 @@
+// Send.go provides input fed dynamically into a running mocked Stdin. With SetInteractive, the input is sent with Send,
+// SendLine and SendEOF, so that a test can decide the next input based on the output of the program. With SetChan, the
+// input is received from a channel. In both cases, the input is written by the same go routine as any other input with
+// the delay, visibility and all other settings of the mocked Stdin.
+//
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock
+
+// Import go standard library packages and tserr
+import (
+	"errors" // errors
+	"io"     // io
+	"sync"   // sync
+
+	"github.com/thorstenrie/tserr" // tserr
+)
+
+// ErrSendClosed is returned by Send, SendLine and SendEOF, if the input has already been ended with SendEOF.
+var ErrSendClosed = errors.New("input ended with SendEOF")
+
+// stopper is implemented by dynamic input sources, which can be stopped while a read is blocked waiting for input.
+type stopper interface {
+	// stop unblocks pending and future reads, which return err
+	stop(err error)
+}
+
+// halt holds the state of stopping a dynamic input source or of releasing the hold of KeepOpen.
+type halt struct {
+	once sync.Once     // Stops only once
+	done chan struct{} // Closed when stopped
+	err  error         // Error returned by reads after stopping
+}
+
+// feeder is an io.Reader on the input sent with Send, SendLine and SendEOF. Reads block until input is sent, the input is ended or the feeder is stopped.
+type feeder struct {
+	halt
+	mu    sync.Mutex    // Mutex for buf and eof
+	buf   []byte        // Input sent, but not read yet
+	eof   bool          // True if the input has been ended
+	ready chan struct{} // Notifies a blocked read of new input or the end of the input
+}
+
+// chanReader is an io.Reader on the strings received from a channel. The input ends when the channel is closed.
+type chanReader struct {
+	halt
+	c   <-chan string // Channel of the input
+	buf []byte        // Input received, but not read yet
+}
+
+// SetInteractive sets the input of the mocked Stdin to the input sent with Send, SendLine and SendEOF and replaces os.Stdin. The input
+// may be sent before or during the run. The run waits for input until SendEOF is called or the run is canceled. See SetReader.
+func (stdin *MockStdin) SetInteractive() error {
+	// Retrieve a new feeder
+	f := &feeder{halt: halt{done: make(chan struct{})}, ready: make(chan struct{}, 1)}
+	// Set the input to f
+	if e := stdin.setInput(f, nil); e != nil {
+		return e
+	}
+	// Store f for sending input
+	stdin.feed.Set(f)
+	// Return nil
+	return nil
+}
+
+// SetChan sets the input of the mocked Stdin to the strings received from c and replaces os.Stdin. The input ends when c is closed.
+// The run waits for input from c until c is closed or the run is canceled. It returns an error if c is nil. See SetReader.
+func (stdin *MockStdin) SetChan(c <-chan string) error {
+	// Return an error if c is nil
+	if c == nil {
+		return tserr.NilPtr()
+	}
+	// Set the input to a reader on c
+	return stdin.setInput(&chanReader{halt: halt{done: make(chan struct{})}, c: c}, nil)
+}
+
+// WithInteractive returns an option to set the input of the mocked Stdin to the input sent with Send, SendLine and SendEOF.
+// The option replaces os.Stdin. See SetInteractive.
+func WithInteractive() Option {
+	return inputOption(func(stdin *MockStdin) error {
+		return stdin.SetInteractive()
+	})
+}
+
+// WithChan returns an option to set the input of the mocked Stdin to the strings received from c. The option replaces os.Stdin. See SetChan.
+func WithChan(c <-chan string) Option {
+	return inputOption(func(stdin *MockStdin) error {
+		return stdin.SetChan(c)
+	})
+}
+
+// Send sends s as input to the mocked Stdin. It does not block. The input is written with the delay and visibility of the mocked Stdin.
+// In line mode, a line is written after its newline has been sent. In raw mode, the input sent is written as soon as it is available,
+// without waiting for a newline or a full chunk. With KeyNotation, an incomplete key notation at the end of s is written after it has
+// been completed. Send is safe to call from any go routine. It returns an error, if the input is not set with SetInteractive, if the
+// input has been ended with SendEOF or if the run has been canceled.
+func (stdin *MockStdin) Send(s string) error {
+	// Retrieve the feeder
+	f, e := stdin.feeder()
+	// Return an error if the feeder is not available
+	if e != nil {
+		return e
+	}
+	// Add s to the input and notify a blocked read
+	return f.send(s, false)
+}
+
+// SendLine sends s followed by a newline as input to the mocked Stdin. See Send.
+func (stdin *MockStdin) SendLine(s string) error {
+	// Send s followed by a newline
+	return stdin.Send(s + "\n")
+}
+
+// SendEOF ends the input sent to the mocked Stdin. The run finishes after all input sent before has been written. If the mocked Stdin
+// is held open with KeepOpen, SendEOF also ends holding open and end-of-file is sent after the end of the input. It returns an error,
+// if the input is neither set with SetInteractive nor held open, if the input has already been ended or if the run has been canceled.
+func (stdin *MockStdin) SendEOF() error {
+	// Retrieve the hold of the current input, if held open
+	var h *halt
+	if stdin.keep.Get() {
+		h = stdin.hold.Get()
+	}
+	// Retrieve the feeder
+	f, e := stdin.feeder()
+	// Return an error if the input is neither set with SetInteractive nor held open
+	if (e != nil) && (h == nil) {
+		return e
+	}
+	// End the input sent and notify a blocked read, if set with SetInteractive
+	if f != nil {
+		if e := f.send("", true); e != nil {
+			return e
+		}
+	} else if h.released() {
+		// Return an error if holding open has already been ended
+		return tserr.Op(&tserr.OpArgs{Op: "SendEOF", Fn: "KeepOpen", Err: ErrSendClosed})
+	}
+	// End holding open, if held open
+	if h != nil {
+		h.stop(nil)
+	}
+	// Return nil
+	return nil
+}
+
+// feeder returns the feeder of the input set with SetInteractive. It returns an error, if the input is not set with SetInteractive.
+func (stdin *MockStdin) feeder() (*feeder, error) {
+	// Retrieve the feeder
+	f := stdin.feed.Get()
+	// Return an error if the feeder is not set
+	if f == nil {
+		return nil, tserr.NotSet("SetInteractive")
+	}
+	// Return the feeder
+	return f, nil
+}
+
+// stop unblocks pending and future reads, which return err.
+func (h *halt) stop(err error) {
+	h.once.Do(func() {
+		h.err = err
+		close(h.done)
+	})
+}
+
+// released returns true, if stopped.
+func (h *halt) released() bool {
+	select {
+	// Return true, if stopped
+	case <-h.done:
+		return true
+	// Return false otherwise
+	default:
+		return false
+	}
+}
+
+// stopped returns the error of stopping, if stopped. Otherwise, it returns nil.
+func (h *halt) stopped() error {
+	select {
+	// Return the error of stopping, if stopped
+	case <-h.done:
+		return h.err
+	// Return nil otherwise
+	default:
+		return nil
+	}
+}
+
+// send adds s to the input and ends the input, if eof is true. It notifies a blocked read. It returns an error, if the input
+// has already been ended or if the feeder is stopped.
+func (f *feeder) send(s string, eof bool) error {
+	// Return an error if the feeder is stopped
+	if e := f.stopped(); e != nil {
+		return tserr.Op(&tserr.OpArgs{Op: "Send", Fn: s, Err: e})
+	}
+	// Lock the mutex
+	f.mu.Lock()
+	// Defer unlocking the mutex
+	defer f.mu.Unlock()
+	// Return an error if the input has already been ended
+	if f.eof {
+		return tserr.Op(&tserr.OpArgs{Op: "Send", Fn: s, Err: ErrSendClosed})
+	}
+	// Add s to the input and end the input, if eof is true
+	f.buf, f.eof = append(f.buf, s...), eof
+	// Notify a blocked read without blocking
+	select {
+	case f.ready <- struct{}{}:
+	default:
+	}
+	// Return nil
+	return nil
+}
+
+// Read reads the input sent into p. It blocks until input is sent, the input is ended or the feeder is stopped. It returns
+// io.EOF at the end of the input and the error of stopping, if stopped.
+func (f *feeder) Read(p []byte) (int, error) {
+	for {
+		// Return the error of stopping, if stopped
+		if e := f.stopped(); e != nil {
+			return 0, e
+		}
+		// Lock the mutex
+		f.mu.Lock()
+		// Copy the input sent into p, if any
+		if len(f.buf) > 0 {
+			n := copy(p, f.buf)
+			f.buf = f.buf[n:]
+			f.mu.Unlock()
+			return n, nil
+		}
+		// Retrieve whether the input has been ended
+		eof := f.eof
+		// Unlock the mutex
+		f.mu.Unlock()
+		// Return io.EOF at the end of the input
+		if eof {
+			return 0, io.EOF
+		}
+		// Wait for new input, the end of the input or stopping
+		select {
+		case <-f.ready:
+		case <-f.done:
+		}
+	}
+}
+
+// Read reads the strings received from the channel into p. It blocks until a string is received, the channel is closed or the
+// reader is stopped. It returns io.EOF, if the channel is closed, and the error of stopping, if stopped.
+func (c *chanReader) Read(p []byte) (int, error) {
+	// Receive the next string, if all input received has been read
+	for len(c.buf) == 0 {
+		select {
+		// Return the error of stopping, if stopped
+		case <-c.done:
+			return 0, c.err
+		// Store the received string or return io.EOF, if the channel is closed
+		case s, ok := <-c.c:
+			if !ok {
+				return 0, io.EOF
+			}
+			c.buf = []byte(s)
+		}
+	}
+	// Copy the input received into p
+	n := copy(p, c.buf)
+	c.buf = c.buf[n:]
+	// Return the number of bytes copied
+	return n, nil
+}
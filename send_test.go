@@ -0,0 +1,254 @@
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock_test
+
+// Import go standard library packages as well as tserr and tsmock
+import (
+	"bufio"   // bufio
+	"bytes"   // bytes
+	"context" // context
+	"errors"  // errors
+	"io"      // io
+	"os"      // os
+	"testing" // testing
+	"time"    // time
+
+	"github.com/thorstenrie/tserr"  // tserr
+	"github.com/thorstenrie/tsmock" // tsmock
+)
+
+// TestSend tests sending input to a running mocked Stdin. The test fails if the lines read from os.Stdin do not equal the lines
+// sent, if the echo does not equal the lines sent, if os.Stdin does not end after SendEOF or if Send does not fail after SendEOF.
+func TestSend(t *testing.T) {
+	// Echo of the input
+	var echo bytes.Buffer
+	// Retrieve a new mocked Stdin with interactive input
+	stdin, e := tsmock.NewStdin(tsmock.WithEcho(&echo), tsmock.WithInteractive())
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Defer restoring Stdin
+	defer stdin.Restore()
+	// Send the first line before the run. The test fails if SendLine returns an error.
+	if e := stdin.SendLine("Aragorn"); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "SendLine", Fn: "Aragorn", Err: e}))
+	}
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Retrieve a reader on os.Stdin
+	r := bufio.NewReader(os.Stdin)
+	// The test fails if the first line read does not equal the line sent
+	testSendRead(r, "Aragorn\n", t)
+	// Send the second line in two parts during the run. The test fails if Send returns an error.
+	for _, s := range []string{"Gan", "dalf\n"} {
+		if e := stdin.Send(s); e != nil {
+			t.Error(tserr.Op(&tserr.OpArgs{Op: "Send", Fn: s, Err: e}))
+		}
+	}
+	// The test fails if the second line read does not equal the line sent
+	testSendRead(r, "Gandalf\n", t)
+	// End the input. The test fails if SendEOF returns an error.
+	if e := stdin.SendEOF(); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "SendEOF", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if os.Stdin does not end
+	if _, e := r.ReadString('\n'); e != io.EOF {
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "ReadString", Actual: "not io.EOF", Want: "io.EOF"}))
+	}
+	// The test fails if the run returns an error
+	if e := stdin.Wait(context.Background()); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Wait", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if Send does not return ErrSendClosed after SendEOF
+	if e := stdin.SendLine("Gimli"); !errors.Is(e, tsmock.ErrSendClosed) {
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "SendLine", Actual: "not ErrSendClosed", Want: "ErrSendClosed"}))
+	}
+	// The test fails if the echo does not equal the lines sent
+	if echo.String() != "Aragorn\nGandalf\n" {
+		t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "echo", Actual: echo.String(), Want: "Aragorn\nGandalf\n"}))
+	}
+}
+
+// TestSendRawChunk tests Send in raw mode with a chunk size larger than the input sent. The test fails if the input sent
+// is not read without a full chunk.
+func TestSendRawChunk(t *testing.T) {
+	testSendAvailable([]string{"ab"}, "ab", t, tsmock.WithRaw(true), tsmock.WithChunk(8))
+}
+
+// TestSendRawLine tests Send in raw mode with input sent without a newline. The test fails if the input sent is not read
+// without a newline.
+func TestSendRawLine(t *testing.T) {
+	testSendAvailable([]string{"ab"}, "ab", t, tsmock.WithRaw(true))
+}
+
+// TestSendRawKeys tests Send in raw mode with key notation and a single key sent without a newline. The test fails if the
+// byte sequence of the key is not read without a newline.
+func TestSendRawKeys(t *testing.T) {
+	testSendAvailable([]string{"<Down>"}, "\x1b[B", t, tsmock.WithRaw(true), tsmock.WithKeyNotation(true))
+}
+
+// TestSendRawKeysSplit tests Send in raw mode with key notation and a key sent in two parts. The test fails if the byte
+// sequence of the key is not read after the second part has been sent.
+func TestSendRawKeysSplit(t *testing.T) {
+	testSendAvailable([]string{"<Do", "wn>"}, "\x1b[B", t, tsmock.WithRaw(true), tsmock.WithKeyNotation(true))
+}
+
+// TestSendNotSet tests Send to return an error, if the input is not set with SetInteractive. The test fails if Send returns nil.
+func TestSendNotSet(t *testing.T) {
+	// Retrieve a new mocked Stdin with a string as input
+	stdin, e := tsmock.NewStdin(tsmock.WithVisibility(false), tsmock.WithString(contents))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Defer restoring Stdin
+	defer stdin.Restore()
+	// The test fails if Send returns nil
+	if e := stdin.Send("Aragorn\n"); e == nil {
+		t.Error(tserr.NilFailed("Send"))
+	}
+}
+
+// TestSendCancel tests canceling a run waiting for input sent with Send. The test fails if the run is not canceled, if the
+// run returns an error or if Send does not fail after cancellation.
+func TestSendCancel(t *testing.T) {
+	// Retrieve a new mocked Stdin with interactive input
+	stdin, e := tsmock.NewStdin(tsmock.WithVisibility(false), tsmock.WithInteractive())
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Defer restoring Stdin
+	defer stdin.Restore()
+	// Retrieve a context with a cancel function
+	ctx, cancel := context.WithCancel(context.Background())
+	// Mock Stdin
+	if e := stdin.Run(ctx); e != nil {
+		// The test fails if Run returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Cancel the run waiting for input
+	cancel()
+	// The test fails if the run returns an error
+	if e := stdin.Wait(context.Background()); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Wait", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if the run is not canceled
+	if !stdin.Result().Cancelled {
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "Result", Actual: "not canceled", Want: "canceled"}))
+	}
+	// The test fails if Send returns nil after cancellation
+	if e := stdin.Send("Aragorn\n"); e == nil {
+		t.Error(tserr.NilFailed("Send"))
+	}
+}
+
+// TestSendChan tests the mocked Stdin with input received from a channel. The test fails if the input read from os.Stdin
+// does not equal the strings sent to the channel or if any error occurs.
+func TestSendChan(t *testing.T) {
+	// Channel of the input
+	c := make(chan string)
+	// Retrieve a new mocked Stdin with the channel as input
+	stdin, e := tsmock.NewStdin(tsmock.WithVisibility(false), tsmock.WithChan(c))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Defer restoring Stdin
+	defer stdin.Restore()
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Send the contents line by line and close the channel
+	go func() {
+		for _, l := range bytes.SplitAfter([]byte(contents), []byte("\n")) {
+			c <- string(l)
+		}
+		close(c)
+	}()
+	// Read all input. The test fails if ReadAll returns an error.
+	b, e := io.ReadAll(os.Stdin)
+	if e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "ReadAll", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if the input does not equal the contents
+	if string(b) != contents {
+		t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "input", Actual: string(b), Want: contents}))
+	}
+}
+
+// TestSendChanNil tests SetChan to return an error, if the channel is nil. The test fails if SetChan returns nil.
+func TestSendChanNil(t *testing.T) {
+	// The test fails if NewStdin returns nil for a nil channel
+	if _, e := tsmock.NewStdin(tsmock.WithChan(nil)); e == nil {
+		t.Error(tserr.NilFailed("SetChan"))
+	}
+}
+
+// testSendRead reads the next line from r. The test fails if reading fails or if the line does not equal want.
+func testSendRead(r *bufio.Reader, want string, t *testing.T) {
+	// Panic if t is nil
+	if t == nil {
+		panic(tserr.NilPtr())
+	}
+	// Read the next line. The test fails if ReadString returns an error.
+	l, e := r.ReadString('\n')
+	if e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "ReadString", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if the line does not equal want
+	if l != want {
+		t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "line", Actual: l, Want: want}))
+	}
+}
+
+// testSendAvailable sends each string of sends to a new running mocked Stdin configured with opts and interactive input without
+// ending the input. The test fails if want is not read from os.Stdin within a second.
+func testSendAvailable(sends []string, want string, t *testing.T, opts ...tsmock.Option) {
+	// Panic if t is nil
+	if t == nil {
+		panic(tserr.NilPtr())
+	}
+	// Retrieve a new mocked Stdin configured with opts and interactive input
+	stdin, e := tsmock.NewStdin(append(opts, tsmock.WithVisibility(false), tsmock.WithInteractive())...)
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Defer restoring Stdin
+	defer stdin.Restore()
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Send each string. The test fails if Send returns an error.
+	for _, s := range sends {
+		if e := stdin.Send(s); e != nil {
+			t.Error(tserr.Op(&tserr.OpArgs{Op: "Send", Fn: s, Err: e}))
+		}
+	}
+	// Read the input sent in a go routine
+	c := make(chan string, 1)
+	go func() {
+		p := make([]byte, len(want))
+		n, _ := io.ReadFull(os.Stdin, p)
+		c <- string(p[:n])
+	}()
+	// The test fails if the input read does not equal want or if it is not read within a second
+	select {
+	case a := <-c:
+		if a != want {
+			t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "input", Actual: a, Want: want}))
+		}
+	case <-time.After(time.Second):
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "Read", Actual: "blocked", Want: want}))
+	}
+}
@@ -0,0 +1,125 @@
+// Signal.go provides signals sent by the mocked Stdin at scripted points of the input. Signals are sent to the current process
+// or to the process set with SignalTarget, for example a child process. Signals are named as in the shell, e.g., INT or SIGINT.
+// The supported signals depend on the platform.
+//
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock
+
+// Import go standard library packages and tserr
+import (
+	"os"      // os
+	"slices"  // slices
+	"sort"    // sort
+	"strings" // strings
+
+	"github.com/thorstenrie/tserr" // tserr
+)
+
+// scheduled is a signal scheduled to be sent after a number of lines has been written.
+type scheduled struct {
+	line int       // Number of lines written before the signal is sent
+	sig  os.Signal // Signal
+}
+
+// SignalAt schedules the signal sig to be sent after line has been written into the mocked Stdin. If line is zero, the signal is sent
+// before the first line. The signal is sent to the current process or to the process set with SignalTarget. Signals scheduled for the same
+// line are sent in the order of scheduling. SignalAt returns an error if sig is nil, if line is negative or if the mocked Stdin is executing.
+func (stdin *MockStdin) SignalAt(line int, sig os.Signal) error {
+	// Return an error if sig is nil
+	if sig == nil {
+		return tserr.NilPtr()
+	}
+	// Return an error if line is negative
+	if line < 0 {
+		return tserr.Higher(&tserr.HigherArgs{Var: "line", Actual: int64(line), LowerBound: 0})
+	}
+	// Return an error if mocked Stdin is executing
+	if e := stdin.notRunning(); e != nil {
+		return e
+	}
+	// Add the signal to the scheduled signals sorted by line
+	return stdin.sigs.Update(func(s []scheduled) ([]scheduled, error) {
+		// Retrieve the position after all signals scheduled up to line
+		k := sort.Search(len(s), func(k int) bool { return s[k].line > line })
+		// Insert the signal at the position
+		return slices.Insert(s, k, scheduled{line: line, sig: sig}), nil
+	})
+}
+
+// SignalTarget sets the process receiving the signals to p. If p is nil, the signals are sent to the current process, which is the
+// default. SignalTarget returns an error if the mocked Stdin is executing.
+func (stdin *MockStdin) SignalTarget(p *os.Process) error {
+	// Return an error if mocked Stdin is executing
+	if e := stdin.notRunning(); e != nil {
+		return e
+	}
+	// Set the process receiving the signals to p
+	stdin.target.Set(p)
+	// Return nil
+	return nil
+}
+
+// WithSignalAt returns an option to schedule the signal sig to be sent after line has been written. See SignalAt.
+func WithSignalAt(line int, sig os.Signal) Option {
+	return func(stdin *MockStdin) error {
+		return stdin.SignalAt(line, sig)
+	}
+}
+
+// WithSignalTarget returns an option to set the process receiving the signals to p. See SignalTarget.
+func WithSignalTarget(p *os.Process) Option {
+	return func(stdin *MockStdin) error {
+		return stdin.SignalTarget(p)
+	}
+}
+
+// signalLines sends the scheduled signals s starting at index k, which are due after lines have been written. It returns
+// the index of the next signal not due yet. It returns an error, if sending a signal fails.
+func (stdin *MockStdin) signalLines(s []scheduled, k, lines int) (int, error) {
+	// Send the due signals
+	for ; (k < len(s)) && (s[k].line <= lines); k++ {
+		// Return an error if sending the signal fails
+		if e := stdin.signal(s[k].sig); e != nil {
+			return k + 1, e
+		}
+	}
+	// Return the index of the next signal
+	return k, nil
+}
+
+// lookupSignal returns the signal with name n, e.g., INT or SIGINT. The name is not case-sensitive. It returns an error,
+// if the signal is not supported on the platform.
+func lookupSignal(n string) (os.Signal, error) {
+	// Retrieve the signal without the prefix SIG
+	sig, ok := signals[strings.TrimPrefix(strings.ToUpper(n), "SIG")]
+	// Return an error if the signal is not supported
+	if !ok {
+		return nil, tserr.NotExistent("signal " + n)
+	}
+	// Return the signal
+	return sig, nil
+}
+
+// signal sends the signal sig to the process set with SignalTarget or, if not set, to the current process. It returns an error,
+// if sending the signal fails.
+func (stdin *MockStdin) signal(sig os.Signal) error {
+	// Retrieve the process receiving the signal
+	p := stdin.target.Get()
+	// Retrieve the current process, if the process is not set
+	if p == nil {
+		var e error
+		p, e = os.FindProcess(os.Getpid())
+		// Return an error if retrieving the current process fails
+		if e != nil {
+			return tserr.NotAvailable(&tserr.NotAvailableArgs{S: "process", Err: e})
+		}
+	}
+	// Send the signal to the process
+	if e := p.Signal(sig); e != nil {
+		return tserr.Op(&tserr.OpArgs{Op: "send signal", Fn: sig.String(), Err: e})
+	}
+	// Return nil
+	return nil
+}
@@ -0,0 +1,19 @@
+//go:build !unix
+
+// Signal_other.go provides the signals supported by the mocked Stdin on platforms other than Unix.
+//
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock
+
+// Import go standard library package os
+import (
+	"os" // os
+)
+
+// signals maps the names of the supported signals without the prefix SIG to the signals.
+var signals = map[string]os.Signal{
+	"INT":  os.Interrupt,
+	"KILL": os.Kill,
+}
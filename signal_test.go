@@ -0,0 +1,113 @@
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock_test
+
+// Import go standard library packages as well as tserr and tsmock
+import (
+	"bufio"     // bufio
+	"context"   // context
+	"os"        // os
+	"os/exec"   // os/exec
+	"os/signal" // os/signal
+	"runtime"   // runtime
+	"testing"   // testing
+	"time"      // time
+
+	"github.com/thorstenrie/tserr"  // tserr
+	"github.com/thorstenrie/tsmock" // tsmock
+)
+
+// TestSignalAt tests a signal scheduled after the first line. The test fails if the signal is not received after the first line
+// has been read or if Restore returns an error.
+func TestSignalAt(t *testing.T) {
+	// Skip the test on Windows, which does not support sending signals to the process
+	if runtime.GOOS == "windows" {
+		t.Skip("sending signals is not supported on windows")
+	}
+	// Channel receiving the signal
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt)
+	// Defer stopping the notification
+	defer signal.Stop(c)
+	// Retrieve a new mocked Stdin sending the signal after the first line
+	stdin, e := tsmock.NewStdin(tsmock.WithVisibility(false), tsmock.WithSignalAt(1, os.Interrupt), tsmock.WithString("Aragorn\nBoromir\n"))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Read the first line. The test fails if reading fails.
+	s := bufio.NewScanner(os.Stdin)
+	if !s.Scan() {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Scan", Fn: "Stdin", Err: s.Err()}))
+	}
+	// The test fails if the signal is not received within a second
+	select {
+	case <-c:
+	case <-time.After(time.Second):
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "SignalAt", Actual: "not received", Want: "received"}))
+	}
+	// Restore Stdin. The test fails if Restore returns an error.
+	if e := stdin.Restore(); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Restore", Fn: "Stdin", Err: e}))
+	}
+}
+
+// TestSignalTarget tests a signal sent to a child process before the first line. The test fails if the child process
+// is not killed by the signal or if Restore returns an error.
+func TestSignalTarget(t *testing.T) {
+	// Skip the test, if the command sleep is not available
+	if _, e := exec.LookPath("sleep"); e != nil {
+		t.Skip("command sleep is not available")
+	}
+	// Start a child process
+	cmd := exec.Command("sleep", "10")
+	if e := cmd.Start(); e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "Start", Fn: "sleep", Err: e}))
+	}
+	// Retrieve a new mocked Stdin killing the child process before the first line
+	stdin, e := tsmock.NewStdin(tsmock.WithVisibility(false), tsmock.WithSignalTarget(cmd.Process), tsmock.WithSignalAt(0, os.Kill), tsmock.WithString("Aragorn\n"))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		cmd.Process.Kill()
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Wait for the run to finish
+	stdin.Wait(context.Background())
+	// The test fails if the child process exits successfully
+	if e := cmd.Wait(); e == nil {
+		t.Error(tserr.NilFailed("Wait"))
+	}
+	// Restore Stdin. The test fails if Restore returns an error.
+	if e := stdin.Restore(); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Restore", Fn: "Stdin", Err: e}))
+	}
+}
+
+// TestSignalAtInvalid tests SignalAt with a nil signal and a negative line. The test fails if SignalAt returns nil.
+func TestSignalAtInvalid(t *testing.T) {
+	// Retrieve a new mocked Stdin
+	stdin, e := tsmock.NewStdin()
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if SignalAt returns nil for a nil signal
+	if e := stdin.SignalAt(1, nil); e == nil {
+		t.Error(tserr.NilFailed("SignalAt"))
+	}
+	// The test fails if SignalAt returns nil for a negative line
+	if e := stdin.SignalAt(-1, os.Interrupt); e == nil {
+		t.Error(tserr.NilFailed("SignalAt"))
+	}
+}
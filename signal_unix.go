@@ -0,0 +1,30 @@
+//go:build unix
+
+// Signal_unix.go provides the signals supported by the mocked Stdin on Unix platforms.
+//
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock
+
+// Import go standard library packages os and syscall
+import (
+	"os"      // os
+	"syscall" // syscall
+)
+
+// signals maps the names of the supported signals without the prefix SIG to the signals.
+var signals = map[string]os.Signal{
+	"ALRM":  syscall.SIGALRM,
+	"CONT":  syscall.SIGCONT,
+	"HUP":   syscall.SIGHUP,
+	"INT":   syscall.SIGINT,
+	"KILL":  syscall.SIGKILL,
+	"PIPE":  syscall.SIGPIPE,
+	"QUIT":  syscall.SIGQUIT,
+	"TERM":  syscall.SIGTERM,
+	"TSTP":  syscall.SIGTSTP,
+	"USR1":  syscall.SIGUSR1,
+	"USR2":  syscall.SIGUSR2,
+	"WINCH": syscall.SIGWINCH,
+}
@@ -0,0 +1,115 @@
+// State.go provides the lifecycle state of the mocked Stdin. The mocked Stdin is Idle until its input is set, Armed after its input
+// is set and Running while it is executing. After the run, the mocked Stdin is either Completed, Cancelled or Failed. Restore returns
+// the mocked Stdin to Idle. Misuse of the lifecycle is reported with sentinel errors, which can be checked with errors.Is.
+//
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock
+
+// Import go standard library packages and tserr
+import (
+	"errors" // errors
+	"fmt"    // fmt
+
+	"github.com/thorstenrie/tserr" // tserr
+)
+
+// State is the lifecycle state of the mocked Stdin.
+type State int
+
+const (
+	Idle      State = iota // Input is not set
+	Armed                  // Input is set, but not executing
+	Running                // Executing
+	Completed              // All input has been written
+	Cancelled              // Execution was canceled before all input has been written
+	Failed                 // Execution failed
+)
+
+var (
+	// ErrRunning is returned, if an operation is not allowed while the mocked Stdin is running.
+	ErrRunning = errors.New("mocked Stdin is running")
+	// ErrNotArmed is returned, if Run is called while the mocked Stdin is not armed.
+	ErrNotArmed = errors.New("mocked Stdin is not armed")
+	// ErrInUse is returned, if another mocked Stdin instance replaces os.Stdin.
+	ErrInUse = errors.New("os.Stdin is replaced by another mocked Stdin")
+)
+
+// String returns the name of the state s.
+func (s State) String() string {
+	switch s {
+	case Idle:
+		return "Idle"
+	case Armed:
+		return "Armed"
+	case Running:
+		return "Running"
+	case Completed:
+		return "Completed"
+	case Cancelled:
+		return "Cancelled"
+	case Failed:
+		return "Failed"
+	}
+	// Return the number of an unknown state
+	return fmt.Sprintf("State(%d)", int(s))
+}
+
+// State returns the current lifecycle state of the mocked Stdin.
+func (stdin *MockStdin) State() State {
+	// Return the current state
+	return stdin.state.Get()
+}
+
+// notRunning returns an error wrapping ErrRunning, if the mocked Stdin is running. Otherwise, it returns nil.
+func (stdin *MockStdin) notRunning() error {
+	// Return an error if the mocked Stdin is running
+	if stdin.State() == Running {
+		return errRunning()
+	}
+	// Return nil
+	return nil
+}
+
+// start sets the state to Running, if the mocked Stdin is armed. It returns an error wrapping ErrRunning or ErrNotArmed otherwise.
+func (stdin *MockStdin) start() error {
+	// Set the state to Running, if the current state is Armed
+	return stdin.state.Update(func(s State) (State, error) {
+		switch s {
+		// Return an error if the mocked Stdin is running
+		case Running:
+			return s, errRunning()
+		// Set the state to Running, if the mocked Stdin is armed
+		case Armed:
+			return Running, nil
+		}
+		// Return an error if the mocked Stdin is not armed
+		return s, fmt.Errorf("%w: %w", ErrNotArmed, tserr.NotSet("Mocked Stdin"))
+	})
+}
+
+// finish sets the state after the run with result res to Completed, Cancelled or Failed.
+func (stdin *MockStdin) finish(res Result) {
+	switch {
+	// Set the state to Failed, if the run failed
+	case res.Err != nil:
+		stdin.state.Set(Failed)
+	// Set the state to Cancelled, if the run was canceled
+	case res.Cancelled:
+		stdin.state.Set(Cancelled)
+	// Set the state to Completed otherwise
+	default:
+		stdin.state.Set(Completed)
+	}
+}
+
+// errRunning returns an error wrapping ErrRunning.
+func errRunning() error {
+	return fmt.Errorf("%w: %w", ErrRunning, tserr.Locked("Mocked Stdin"))
+}
+
+// errInUse returns an error wrapping ErrInUse.
+func errInUse() error {
+	return fmt.Errorf("%w: %w", ErrInUse, tserr.Locked("os.Stdin"))
+}
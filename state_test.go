@@ -0,0 +1,137 @@
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock_test
+
+// Import go standard library packages as well as tserr and tsmock
+import (
+	"context" // context
+	"errors"  // errors
+	"strings" // strings
+	"testing" // testing
+	"time"    // time
+
+	"github.com/thorstenrie/tserr"  // tserr
+	"github.com/thorstenrie/tsmock" // tsmock
+)
+
+// TestStateCompleted tests the states Idle, Armed, Completed and Idle again after Restore. The test fails if
+// the mocked Stdin is in an unexpected state.
+func TestStateCompleted(t *testing.T) {
+	// Run a mocked Stdin with the contents and expect the state Completed after the run
+	testState(contents, false, tsmock.Completed, t)
+}
+
+// TestStateCancelled tests the states Idle, Armed, Running, Cancelled and Idle again after Restore. The test fails if
+// the mocked Stdin is in an unexpected state.
+func TestStateCancelled(t *testing.T) {
+	// Run a mocked Stdin with the contents, cancel it and expect the state Cancelled after the run
+	testState(contents, true, tsmock.Cancelled, t)
+}
+
+// TestStateFailed tests the states Idle, Armed, Failed and Idle again after Restore. The test fails if
+// the mocked Stdin is in an unexpected state.
+func TestStateFailed(t *testing.T) {
+	// Run a mocked Stdin with a line exceeding the maximum token size and expect the state Failed after the run
+	testState(strings.Repeat("x", 100*1024), false, tsmock.Failed, t)
+}
+
+// TestStateErrors tests that misuse of the lifecycle returns the sentinel errors ErrNotArmed, ErrRunning and ErrInUse.
+// The test fails if an error does not match the expected sentinel error with errors.Is.
+func TestStateErrors(t *testing.T) {
+	// Retrieve a new mocked Stdin with a delay of one minute
+	stdin, e := tsmock.NewStdin(tsmock.WithDelay(time.Minute), tsmock.WithVisibility(false))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Defer restoring Stdin
+	defer stdin.Restore()
+	// The test fails if Run does not return ErrNotArmed
+	if e := stdin.Run(context.Background()); !errors.Is(e, tsmock.ErrNotArmed) {
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "Run", Actual: "not ErrNotArmed", Want: "ErrNotArmed"}))
+	}
+	// The test fails if SetString returns an error
+	if e := stdin.SetString(contents); e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "SetString", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if SetString of another instance does not return ErrInUse
+	if e := tsmock.Stdin.SetString(contents); !errors.Is(e, tsmock.ErrInUse) {
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "SetString", Actual: "not ErrInUse", Want: "ErrInUse"}))
+	}
+	// The test fails if Run returns an error
+	if e := stdin.Run(context.Background()); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if Run again does not return ErrRunning
+	if e := stdin.Run(context.Background()); !errors.Is(e, tsmock.ErrRunning) {
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "Run", Actual: "not ErrRunning", Want: "ErrRunning"}))
+	}
+	// The test fails if Raw while running does not return ErrRunning
+	if e := stdin.Raw(true); !errors.Is(e, tsmock.ErrRunning) {
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "Raw", Actual: "not ErrRunning", Want: "ErrRunning"}))
+	}
+}
+
+// testState runs a new mocked Stdin with input in and cancels the run, if c is true. The test fails if the mocked Stdin
+// is not Idle before the input is set, not Armed after the input is set, not Running after Run, not in state want after the run
+// or not Idle after Restore.
+func testState(in string, c bool, want tsmock.State, t *testing.T) {
+	// Panic if t is nil
+	if t == nil {
+		panic(tserr.NilPtr())
+	}
+	// Delay of the mocked Stdin, one minute to keep it running if the run is canceled
+	d := time.Duration(0)
+	if c {
+		d = time.Minute
+	}
+	// Retrieve a new mocked Stdin
+	stdin, e := tsmock.NewStdin(tsmock.WithDelay(d), tsmock.WithVisibility(false))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if the mocked Stdin is not Idle
+	testStateEqual(stdin, tsmock.Idle, t)
+	// The test fails if SetString returns an error
+	if e := stdin.SetString(in); e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "SetString", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if the mocked Stdin is not Armed
+	testStateEqual(stdin, tsmock.Armed, t)
+	// Retrieve a context with a cancel function
+	ctx, cancel := context.WithCancel(context.Background())
+	// Defer cancel function
+	defer cancel()
+	// The test fails if Run returns an error
+	if e := stdin.Run(ctx); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Cancel the run, if c is true
+	if c {
+		// The test fails if the mocked Stdin is not Running
+		testStateEqual(stdin, tsmock.Running, t)
+		cancel()
+	}
+	// Wait for the run to finish
+	stdin.Wait(context.Background())
+	// The test fails if the mocked Stdin is not in state want
+	testStateEqual(stdin, want, t)
+	// Restore Stdin
+	stdin.Restore()
+	// The test fails if the mocked Stdin is not Idle
+	testStateEqual(stdin, tsmock.Idle, t)
+}
+
+// testStateEqual fails the test, if the state of stdin does not equal want.
+func testStateEqual(stdin *tsmock.MockStdin, want tsmock.State, t *testing.T) {
+	// Panic if t is nil
+	if t == nil {
+		panic(tserr.NilPtr())
+	}
+	// The test fails if the state of stdin does not equal want
+	if s := stdin.State(); s != want {
+		t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "State", Want: want.String(), Actual: s.String()}))
+	}
+}
@@ -10,9 +10,10 @@ package tsmock
 
 // Import go standard library packages and tserr
 import (
-	"bufio"   // bufio
 	"context" // context
+	"errors"  // errors
 	"fmt"     // fmt
+	"io"      // io
 	"os"      // os
 	"sync"    // sync
 	"time"    // time
@@ -20,17 +21,65 @@ import (
 	"github.com/thorstenrie/tserr" // tserr
 )
 
-// MockStdin contains the internal state of a mocked Stdin. It holds variables for file descriptors, a time delay, an option for visibility and an error, if any.
-// It stores a context cancel function and a sync wait group. Users of the mocked Stdin are expected to use the globally exported instance tsmock.Stdin.
+// MockStdin contains the internal state of a mocked Stdin. It holds variables for file descriptors, a time delay, an option for visibility, a writer for
+// the echo of the input and an error, if any. It stores a context cancel function and a sync wait group. Users of the mocked Stdin may use the globally
+// exported instance tsmock.Stdin or retrieve an isolated instance with NewStdin.
 type MockStdin struct {
-	in, r, w, o *os.File                    // input, pipe and original Stdin file descriptors
-	e           SafeVariable[error]         // Error, if any
-	d           SafeVariable[time.Duration] // Time delay in reading input
-	v           SafeVariable[bool]          // Visibility of input
-	run         SafeVariable[bool]          // True if executing, false otherwise
-	set         SafeVariable[bool]          // True if pip is set, false otherwise
-	cancel      context.CancelFunc          // Context cancel function
-	wg          sync.WaitGroup              // Sync wait group
+	in       io.Reader                   // Input
+	c        io.Closer                   // Closer of the input, if owned by the mocked Stdin
+	r, w, o  *os.File                    // pipe and original Stdin file descriptors
+	errs     SafeVariable[[]error]       // Errors, if any
+	d        SafeVariable[time.Duration] // Time delay in reading input
+	clock    SafeVariable[Clock]         // Clock for all waiting, real clock if nil
+	v        SafeVariable[bool]          // Visibility of input
+	echo     SafeVariable[io.Writer]     // Writer for the echo of visible input, os.Stdout if nil
+	raw      SafeVariable[bool]          // True if the input is passed through byte for byte
+	chunk    SafeVariable[int]           // Size of chunks in raw input mode, line boundaries if zero
+	kind     SafeVariable[Kind]          // Kind of file the program sees as os.Stdin
+	strict   SafeVariable[bool]          // True if unread input and reads past the end of the input are errors
+	lockstep SafeVariable[bool]          // True if the next line is only written after the previous line has been read
+	dir      SafeVariable[bool]          // True if directives in the input are interpreted
+	out      SafeVariable[*MockOutput]   // Captured output awaited by directives
+	sigs     SafeVariable[[]scheduled]   // Signals scheduled at lines of the input
+	target   SafeVariable[*os.Process]   // Process receiving the signals, current process if nil
+	keys     SafeVariable[bool]          // True if the key notation in the input is translated
+	typing   SafeVariable[TypingModel]   // Model for typing the input character by character, nil if written at once
+	mask     SafeVariable[Mask]          // Masking mode of the echo
+	prefix   SafeVariable[string]        // Prefix of the echo
+	color    SafeVariable[Color]         // Color of the echo
+	ebuf     []byte                      // Echo of an incomplete line, styled when the line is complete
+	emu      sync.Mutex                  // Mutex for the echo of an incomplete line
+	feed     SafeVariable[*feeder]       // Input sent with Send, if set with SetInteractive
+	keep     SafeVariable[bool]          // True if the mocked Stdin is held open after the end of the input
+	hold     SafeVariable[*halt]         // Released by SendEOF to end holding open
+	pace     SafeVariable[pacing]        // State of pausing between lines
+	fd0      SafeVariable[bool]          // True if the pipe is installed at file descriptor 0
+	sfd      int                         // Saved file descriptor 0
+	opened   Kind                        // Kind of the opened mocked Stdin
+	tmp      string                      // Name of the temporary file to be removed, if any
+	filled   Result                      // Result of the input written into a regular file by the input setter
+	collect  bool                        // True while NewStdin collects the options setting the input
+	inputs   []Option                    // Options setting the input collected by NewStdin
+	ifd      bool                        // True if the pipe is installed at file descriptor 0 currently
+	wr       SafeVariable[written]       // Input written into Stdin
+	left     SafeVariable[unread]        // Input left unread at the time of Restore
+	tty      bool                        // True if the current pipe is a pseudo-terminal
+	state    SafeVariable[State]         // Lifecycle state
+	done     SafeVariable[chan struct{}] // Closed after execution of the current run finished
+	res      SafeVariable[Result]        // Result of the last run
+	cancel   context.CancelFunc          // Context cancel function
+	wg       sync.WaitGroup              // Sync wait group
+	ewg      sync.WaitGroup              // Sync wait group for the echo of a pseudo-terminal
+}
+
+// Option configures a mocked Stdin retrieved with NewStdin. It returns an error, if the configuration fails.
+type Option func(*MockStdin) error
+
+// owner holds the mocked Stdin instance which currently replaces os.Stdin, if any. Only one instance
+// at a time is allowed to replace os.Stdin.
+var owner struct {
+	stdin *MockStdin // Mocked Stdin instance replacing os.Stdin, nil otherwise
+	mu    sync.Mutex // Mutex
 }
 
 var (
@@ -38,42 +87,177 @@ var (
 	Stdin = newStdin()
 )
 
+// NewStdin returns a new isolated mocked Stdin instance configured with opts. Visibility of stdin is set to true and
+// the delay is set to zero, if not configured otherwise. Options setting the input are applied after all other options,
+// regardless of their order, so that the input is set with the complete configuration. It returns nil and an error, if
+// an option fails. Only one mocked Stdin instance at a time can replace os.Stdin. If another instance already replaces
+// os.Stdin, setting the input of the new instance returns an error until the other instance is restored.
+func NewStdin(opts ...Option) (*MockStdin, error) {
+	// Return an error if any option is nil, before an option replaces os.Stdin
+	for _, opt := range opts {
+		if opt == nil {
+			return nil, tserr.NilPtr()
+		}
+	}
+	// Retrieve a new mocked Stdin instance
+	stdin := newStdin()
+	// Apply all options, except for options setting the input, which are collected
+	stdin.collect = true
+	for _, opt := range opts {
+		// Return an error if opt fails
+		if e := opt(stdin); e != nil {
+			return nil, e
+		}
+	}
+	// Retrieve the collected options setting the input
+	inputs := stdin.inputs
+	stdin.collect, stdin.inputs = false, nil
+	// Apply the options setting the input
+	for _, opt := range inputs {
+		// Return an error if opt fails
+		if e := opt(stdin); e != nil {
+			// Restore os.Stdin, in case the input has been set already
+			stdin.Restore()
+			return nil, e
+		}
+	}
+	// Return the new instance
+	return stdin, nil
+}
+
+// inputOption returns opt as an option setting the input. While NewStdin applies the other options, opt is collected
+// to be applied afterwards. Otherwise, opt is applied immediately.
+func inputOption(opt Option) Option {
+	return func(stdin *MockStdin) error {
+		// Collect opt, if NewStdin applies the other options
+		if stdin.collect {
+			stdin.inputs = append(stdin.inputs, opt)
+			return nil
+		}
+		// Apply opt
+		return opt(stdin)
+	}
+}
+
+// WithDelay returns an option to set the time delay of the mocked Stdin to d. See Delay.
+func WithDelay(d time.Duration) Option {
+	return func(stdin *MockStdin) error {
+		return stdin.Delay(d)
+	}
+}
+
+// WithVisibility returns an option to set the visibility of the input of the mocked Stdin to v. See Visibility.
+func WithVisibility(v bool) Option {
+	return func(stdin *MockStdin) error {
+		stdin.Visibility(v)
+		return nil
+	}
+}
+
+// WithEcho returns an option to set the writer for the echo of visible input of the mocked Stdin to w. See Echo.
+func WithEcho(w io.Writer) Option {
+	return func(stdin *MockStdin) error {
+		return stdin.Echo(w)
+	}
+}
+
+// WithInput returns an option to set the input of the mocked Stdin to in. The option replaces os.Stdin. See Set.
+func WithInput(in *os.File) Option {
+	return inputOption(func(stdin *MockStdin) error {
+		return stdin.Set(in)
+	})
+}
+
 // Retrieve a new mocked Stdin instance. Visibility of stdin is set to true.
 func newStdin() *MockStdin {
 	// Retrieve a new mocked Stdin instance and set o to the original os.Stdin
 	r := &MockStdin{o: os.Stdin}
 	// Set visibility of stdin to true
 	r.v.Set(true)
-	// Mocked stdin is not executing
-	r.run.Set(false)
-	// Mocked stdin is not set
-	r.set.Set(false)
+	// Mocked stdin is idle
+	r.state.Set(Idle)
+	// Mocked stdin has no running execution
+	done := make(chan struct{})
+	close(done)
+	r.done.Set(done)
 	// Return the new instance
 	return r
 }
 
+// acquire sets the mocked Stdin instance replacing os.Stdin to stdin. It returns an error, if another
+// instance already replaces os.Stdin.
+func (stdin *MockStdin) acquire() error {
+	// Lock the mutex
+	owner.mu.Lock()
+	// Defer unlocking the mutex
+	defer owner.mu.Unlock()
+	// Return an error if another instance replaces os.Stdin
+	if (owner.stdin != nil) && (owner.stdin != stdin) {
+		return errInUse()
+	}
+	// Store the original os.Stdin, if stdin does not replace os.Stdin yet
+	if owner.stdin == nil {
+		stdin.o = os.Stdin
+	}
+	// Set stdin as the instance replacing os.Stdin
+	owner.stdin = stdin
+	// Return nil
+	return nil
+}
+
+// release restores the original os.Stdin, if stdin replaces os.Stdin. Otherwise, os.Stdin is left unchanged.
+func (stdin *MockStdin) release() {
+	// Lock the mutex
+	owner.mu.Lock()
+	// Defer unlocking the mutex
+	defer owner.mu.Unlock()
+	// Return if stdin does not replace os.Stdin
+	if owner.stdin != stdin {
+		return
+	}
+	// Restore os.Stdin to original os.Stdin
+	os.Stdin = stdin.o
+	// Reset the instance replacing os.Stdin
+	owner.stdin = nil
+}
+
 // closePipe closes the pipe, if existing.
 func (stdin *MockStdin) closePipe() {
+	// Restore file descriptor 0, if the pipe is installed
+	stdin.uninstall()
 	// Close read file descriptor, if not nil
 	if stdin.r != nil {
 		stdin.r.Close()
 	}
+	// Wait for the echo of a pseudo-terminal to be stopped. The echo stops after the slave side has been closed.
+	stdin.ewg.Wait()
 	// Close write file descriptor, if not nil
 	if stdin.w != nil {
 		stdin.w.Close()
 	}
-	// Close input file descriptor, if not nil
-	if stdin.in != nil {
-		stdin.in.Close()
+	// Close input, if closer is not nil
+	if stdin.c != nil {
+		stdin.c.Close()
+	}
+	// Remove the temporary file, if any
+	if stdin.tmp != "" {
+		os.Remove(stdin.tmp)
 	}
-	// Set the file descriptors to nil
-	stdin.w, stdin.r, stdin.in = nil, nil, nil
+	// Set the file descriptors and the input to nil
+	stdin.w, stdin.r, stdin.in, stdin.c = nil, nil, nil, nil
+	// Reset the input sent with Send and the hold for holding open
+	stdin.feed.Set(nil)
+	stdin.hold.Set(nil)
+	// Reset the pseudo-terminal flag, the kind, the temporary file and its result
+	stdin.tty, stdin.opened, stdin.tmp, stdin.filled = false, KindPipe, "", Result{}
 }
 
-// Restore restores the original os.Stdin. It cancels current execution of the mocked stdin and returns the last occurring error, if any.
+// Restore restores the original os.Stdin. It cancels current execution of the mocked stdin and returns the errors, if any. A write
+// blocked by a full pipe, because the program stops reading, is interrupted. In this case, Restore returns a *LineError wrapping
+// ErrUnconsumed with the number of bytes written or being written, but never consumed.
 func (stdin *MockStdin) Restore() error {
 	// Cancel the current execution of the mocked Stdin, if execution is running
-	if stdin.run.Get() {
+	if stdin.State() == Running {
 		// Return an error if cancel function is nil
 		if stdin.cancel == nil {
 			return tserr.NilPtr()
@@ -85,16 +269,16 @@ func (stdin *MockStdin) Restore() error {
 	}
 	// Wait for the execution of the mocked stdin to be stopped
 	stdin.wg.Wait()
+	// Store the input left unread and check it in strict mode
+	stdin.consumed()
 	// Close existing pipe, if existing
 	stdin.closePipe()
-	// Restore os.Stdin to original os.Stdin
-	os.Stdin = stdin.o
-	// Set mocked stdin execution to false
-	stdin.run.Set(false)
-	// Set mocked stdin to not set
-	stdin.set.Set(false)
-	// Return an error, if any
-	return stdin.e.Get()
+	// Restore os.Stdin to original os.Stdin, if replaced by stdin
+	stdin.release()
+	// Set mocked stdin to idle
+	stdin.state.Set(Idle)
+	// Return the errors, if any
+	return stdin.Err()
 }
 
 // Delay sets a time delay d for the mocked Stdin. If d is set to a value higher than zero, each line input to the mocked Stdin will be delayed by
@@ -116,40 +300,92 @@ func (stdin *MockStdin) Delay(d time.Duration) error {
 func (stdin *MockStdin) Visibility(v bool) {
 	// Set visibility to v
 	stdin.v.Set(v)
+	// Set the ECHO flag of the pseudo-terminal to v, if used
+	if stdin.tty {
+		if e := setEcho(stdin.r, v); e != nil {
+			stdin.fail(e)
+		}
+	}
 }
 
-// Err returns the last occurring error, if any.
+// Echo sets the writer for the echo of visible input to w. If w is nil, the echo is written to os.Stdout, which is
+// the default. Echo returns an error if the mocked Stdin is executing.
+func (stdin *MockStdin) Echo(w io.Writer) error {
+	// Return an error if mocked Stdin is executing
+	if e := stdin.notRunning(); e != nil {
+		return e
+	}
+	// Set echo writer to w
+	stdin.echo.Set(w)
+	// Return nil
+	return nil
+}
+
+// Err returns all errors occurred since the input has been set, joined with errors.Join, or nil if no error occurred.
+// Errors occurring while writing the input are of type *LineError.
 func (stdin *MockStdin) Err() error {
-	// Return las occurring error, if any
-	return stdin.e.Get()
+	// Return the joined errors, if any
+	return errors.Join(stdin.errs.Get()...)
 }
 
-// Set sets the input of the mocked Stdin to in. If a previous mock run is still being executed, Set returns an error.
+// Set sets the input of the mocked Stdin to in and replaces os.Stdin. If a previous mock run is still being executed, Set returns an error
+// wrapping ErrRunning. Set returns an error wrapping ErrInUse, if another mocked Stdin instance replaces os.Stdin. The file in is closed by Restore.
 func (stdin *MockStdin) Set(in *os.File) error {
+	// Return an error if in is nil
+	if in == nil {
+		return tserr.NilPtr()
+	}
+	// Set the input to in, which is closed by the mocked Stdin
+	return stdin.setInput(in, in)
+}
+
+// setInput sets the input of the mocked Stdin to in and replaces os.Stdin. If c is not nil, c is closed together with the pipe.
+// It returns an error, if a previous mock run is still being executed or another mocked Stdin instance replaces os.Stdin.
+func (stdin *MockStdin) setInput(in io.Reader, c io.Closer) error {
 	// Return an error if in is nil
 	if in == nil {
 		return tserr.NilPtr()
 	}
 	// Return an error if mocked Stdin is executing
-	if stdin.run.Get() {
-		return tserr.Locked("Mocked Stdin")
+	if e := stdin.notRunning(); e != nil {
+		return e
 	}
+	// Return an error if another mocked Stdin instance replaces os.Stdin
+	if e := stdin.acquire(); e != nil {
+		return e
+	}
+	// Reset the errors of previous runs
+	stdin.errs.Set(nil)
+	// Reset the input written and left unread of previous runs
+	stdin.wr.Set(written{})
+	stdin.left.Set(unread{})
 	// Close existing pipe, if existing
 	stdin.closePipe()
-	// Retrieve a new pipe
-	var e error
-	stdin.r, stdin.w, e = os.Pipe()
-	// Return an error if retrieving a new pipe fails
-	if (e != nil) || (stdin.w == nil) || (stdin.r == nil) {
+	// Retrieve a new hold for holding open after the end of the input
+	stdin.hold.Set(&halt{done: make(chan struct{})})
+	// Open a new pipe or another kind of file set with Kind
+	if e := stdin.open(); e != nil {
+		stdin.Restore()
+		return e
+	}
+	// Install the pipe at file descriptor 0, if enabled
+	if e := stdin.install(); e != nil {
 		stdin.Restore()
-		return tserr.NotAvailable(&tserr.NotAvailableArgs{S: "os.Pipe", Err: stdin.e.Get()})
+		return e
+	}
+	// Set input and its closer
+	stdin.in, stdin.c = in, c
+	// Write the whole input into a regular file, before it replaces os.Stdin
+	if stdin.opened == KindFile {
+		if e := stdin.fill(); e != nil {
+			stdin.Restore()
+			return e
+		}
 	}
-	// Set input file
-	stdin.in = in
 	// Set os.Stdin to pipe
 	os.Stdin = stdin.r
-	// Set mocked stdin to set
-	stdin.set.Set(true)
+	// Set mocked stdin to armed
+	stdin.state.Set(Armed)
 	// Return nil
 	return nil
 }
@@ -157,78 +393,296 @@ func (stdin *MockStdin) Set(in *os.File) error {
 // Run starts a new go routine to write the input from in into the mocked Stdin.
 // The input can be retrieved through os.Stdin, the same as it would be user input from a terminal.
 // The go routine closes and exits, when all input from in has been processed or if the context is canceled.
-// To execute the delay, the Sleep function is used. If the context is canceled, the execution will stop after the Sleep function completed.
-// It returns an error if the mocked Stdin is already executing.
+// The delay is interrupted immediately, if the context is canceled, so that the execution stops without waiting for the delay to complete.
+// It returns an error wrapping ErrRunning, if the mocked Stdin is already executing, or ErrNotArmed, if the input is not set.
 func (stdin *MockStdin) Run(ctx context.Context) error {
-	// Return an error if the mocked Stdin is already executing
-	if stdin.run.Get() {
-		return tserr.Locked("Mocked Stdin")
-	}
-	// Return an error if the mocked Stdin is not set
-	if !stdin.set.Get() {
-		return tserr.NotSet("Mocked Stdin")
+	// Set the state to Running. Return an error if the mocked Stdin is already executing or not armed.
+	if e := stdin.start(); e != nil {
+		return e
 	}
 	// Add to waitgroup
 	stdin.wg.Add(1)
-	// Set execution to true
-	stdin.run.Set(true)
 	// Retrieve a child context and a cancel function
 	ctx, stdin.cancel = context.WithCancel(ctx)
+	// Retrieve a new channel closed after execution finished
+	done := make(chan struct{})
+	stdin.done.Set(done)
 	// Execute mocked Stdin
-	go stdin.write(ctx)
+	go stdin.write(ctx, done)
 	// Return nil
 	return nil
 }
 
-// write writes text from in into Stdin. It is intended to be executed in a go routine.
-func (stdin *MockStdin) write(ctx context.Context) {
+// write writes text from in into Stdin and stores the result of the run. It closes done after execution finished.
+// It is intended to be executed in a go routine.
+func (stdin *MockStdin) write(ctx context.Context, done chan struct{}) {
 	// Set waitgroup to done after execution finished
 	defer stdin.wg.Done()
-	// Set execution to false after execution finished
-	defer stdin.run.Set(false)
-	// Set an error and stop execution if w is nil
+	// Close done after execution finished
+	defer close(done)
+	// Write the input into Stdin
+	res := stdin.writeInput(ctx)
+	// Add the error, if any
+	if res.Err != nil {
+		stdin.fail(res.Err)
+	}
+	// Store the result of the run
+	stdin.res.Set(res)
+	// Set the state after the run
+	stdin.finish(res)
+}
+
+// writeInput writes text from in into Stdin. It returns the result of the run.
+func (stdin *MockStdin) writeInput(ctx context.Context) (res Result) {
+	// Return the result of the input already written into a regular file
+	if stdin.opened == KindFile {
+		return stdin.filled
+	}
+	// Write the echo of an incomplete last line after execution finished, unless echoed by a pseudo-terminal
+	if !stdin.tty {
+		defer stdin.flushEcho()
+	}
+	// Return an error if w is nil
 	if stdin.w == nil {
-		stdin.e.Set(tserr.NilPtr())
+		res.Err = tserr.NilPtr()
 		return
 	}
-	// Close w after execution finished
-	defer stdin.w.Close()
-	// Set an error and stop execution if in is nil
+	// Last byte written to Stdin
+	last := byte('\n')
+	// Close w or send end-of-file to the pseudo-terminal after execution finished
+	defer func() {
+		// Count a last line without a newline
+		if last != '\n' {
+			res.Lines++
+		}
+		// Add an error, if ending the input fails, unless the write deadline has been exceeded due to cancellation
+		if e := stdin.eof(last); (e != nil) && !errors.Is(e, os.ErrDeadlineExceeded) {
+			res.Err = errors.Join(res.Err, e)
+		}
+	}()
+	// Return an error if in is nil
 	if stdin.in == nil {
-		stdin.e.Set(tserr.NilPtr())
+		res.Err = tserr.NilPtr()
+		return
+	}
+	// Interrupt a write blocked by a full buffer, if the context is canceled and a write may block
+	if stdin.blocking() {
+		stop := context.AfterFunc(ctx, interrupt(stdin.r, stdin.w))
+		// Defer stopping the interruption
+		defer stop()
+	}
+	// Stop waiting for input of a dynamic input source, if the context is canceled
+	if s, ok := stdin.in.(stopper); ok {
+		stopIn := context.AfterFunc(ctx, func() { s.stop(ctx.Err()) })
+		// Defer stopping the interruption and stop the input source before the run ends, if the context is canceled
+		defer func() {
+			stopIn()
+			if ctx.Err() != nil {
+				s.stop(ctx.Err())
+			}
+		}()
+	}
+	// Retrieve the function returning the next part of the input
+	next := stdin.split()
+	// Interpret directives, if enabled
+	dir := stdin.directives()
+	// State of the directives and input skipped by directives
+	var (
+		sc   script
+		skip Result
+	)
+	// Retrieve the scheduled signals
+	sigs := stdin.sigs.Get()
+	// Send the signals scheduled before the first line
+	k, e := stdin.signalLines(sigs, 0, 0)
+	// Return an error with the line context, if sending a signal fails
+	if e != nil {
+		res.Err = &LineError{Line: 1, Err: e}
 		return
 	}
-	// Retrieve a scanner on in
-	s := bufio.NewScanner(stdin.in)
-	// Set break condition to false
-	br := false
-	// Scan scanner on in
-	for s.Scan() {
-		select {
-		// Set break condition to true, if context is canceled
-		case <-ctx.Done():
-			// Break outer loop
-			br = true
-		default: // Otherwise, continue
-		}
-		// Stop scanning if break condition is true
-		if br {
-			break
-		}
-		// Set i to retrieved text from the scanner and add a newline
-		i := s.Text() + "\n"
-		// Write i to Stdin
-		_, err := stdin.w.WriteString(i)
-		// Set an error and stop execution, if WriteString fails
+	for {
+		// Retrieve the next part i of the input
+		i, err := next()
+		// Stop execution, if the context is canceled. The run is finished, if all input has been processed.
+		if ctx.Err() != nil {
+			res.Finished = (len(i) == 0) && (err == io.EOF)
+			res.Cancelled = !res.Finished
+			return
+		}
+		// Number and byte offset of the line of i in the input
+		line, offset := res.Lines+skip.Lines+1, res.Bytes+skip.Bytes
+		// Execute i instead of writing it, if i is a directive
+		if d, ok := parseDirective(i); dir && ok {
+			// Count i as skipped input
+			skip.count(i)
+			// Execute the directive
+			if e := stdin.direct(ctx, &sc, d); ctx.Err() != nil {
+				// Stop execution, if the context is canceled
+				res.Cancelled = true
+				return
+			} else if e != nil {
+				// Return an error with the line context, if the directive fails
+				res.Err = &LineError{Line: line, Offset: offset, Text: string(i), Err: e}
+				return
+			}
+			// End the input, if the directive ended the input
+			if sc.eof {
+				err = io.EOF
+			}
+			// Continue with the next part of the input, if not at the end of the input
+			if err == nil {
+				continue
+			}
+			// Do not write the directive
+			i = nil
+		}
+		// Write i to Stdin, if not empty
+		if len(i) > 0 {
+			// Wait while paused and stop execution, if the context is canceled
+			if !stdin.paced(ctx) {
+				res.Cancelled = true
+				return
+			}
+			// Switch the echo of the pseudo-terminal for hidden lines
+			stdin.mute(&sc)
+			// Write or type i to Stdin
+			n, e := stdin.typeInput(ctx, i)
+			// Count the written bytes and lines
+			res.count(i[:n])
+			// Record the written bytes and lines
+			stdin.record(i[:n])
+			// Return an error with the line context, if Write fails
+			if e != nil {
+				// Report the input never consumed, if the write has been interrupted by the canceled context
+				if ctx.Err() != nil {
+					res.Cancelled = true
+					// Stop execution without an error, if typing has been canceled
+					if errors.Is(e, ctx.Err()) {
+						return
+					}
+					e = stdin.unconsumed(int64(len(i) - n))
+				}
+				res.Err = &LineError{Line: line, Offset: offset, Text: string(i), Err: e}
+				return
+			}
+			// Store the last written byte
+			last = i[len(i)-1]
+			// Echo i if Visibility is true and i is not hidden, unless the pseudo-terminal echoes the input
+			if stdin.v.Get() && !stdin.tty && !sc.hidden {
+				stdin.print(string(i))
+			}
+			// Echo the next line
+			sc.hidden = false
+			// Send the signals scheduled after the lines written
+			if k, e = stdin.signalLines(sigs, k, res.Lines); e != nil {
+				// Return an error with the line context, if sending a signal fails
+				res.Err = &LineError{Line: line, Offset: offset, Text: string(i), Err: e}
+				return
+			}
+		}
+		// Stop execution, if all input has been processed
+		if err == io.EOF {
+			res.Finished = true
+			// Wait for a read past the end of the input in strict mode, unless held open or ended by a directive
+			if stdin.strict.Get() && !stdin.keep.Get() && !sc.eof && stdin.overRead(ctx) {
+				res.Err = fmt.Errorf("%w: after %d bytes", ErrOverRead, res.Bytes)
+			}
+			// Hold the mocked Stdin open, if enabled, unless a directive ended the input
+			if !sc.eof {
+				stdin.holdOpen(ctx)
+			}
+			return
+		}
+		// Return an error with the line context, if reading the input fails
 		if err != nil {
-			stdin.e.Set(err)
+			res.Err = &LineError{Line: line, Offset: offset, Text: string(i), Err: err}
 			return
 		}
-		// Print i if Visibility is true
-		if stdin.v.Get() {
-			fmt.Print(i)
+		// Wait for the program to read the input in lockstep mode
+		if stdin.lockstep.Get() {
+			if e := stdin.drained(ctx); ctx.Err() != nil {
+				// Stop execution, if the context is canceled
+				res.Cancelled = true
+				return
+			} else if e != nil {
+				// Return an error with the line context, if waiting fails
+				res.Err = &LineError{Line: line, Offset: offset, Text: string(i), Err: e}
+				return
+			}
+		}
+		// Retrieve the delay, unless set by a directive
+		d := sc.delay
+		if !sc.delayed {
+			d = stdin.d.Get()
+		}
+		// Wait for the delay. Execution stops with the next part of the input, if the context is canceled.
+		stdin.sleep(ctx, d)
+	}
+}
+
+// sleep waits for the delay d. It returns false, if the context is canceled before d elapsed, and true otherwise.
+func (stdin *MockStdin) sleep(ctx context.Context, d time.Duration) bool {
+	// Return true without waiting, if d is not positive
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	// Retrieve a timer for d from the clock
+	t := stdin.now().NewTimer(d)
+	// Defer stopping the timer
+	defer t.Stop()
+	select {
+	// Return false, if the context is canceled
+	case <-ctx.Done():
+		return false
+	// Return true, if d elapsed
+	case <-t.C():
+		return true
+	}
+}
+
+// interrupt returns a function, which unblocks a write to w blocked by a full buffer, because the program does not read the
+// input. The function sets the write deadline of w to the past. If w does not support deadlines, it closes the read end r.
+func interrupt(r, w *os.File) func() {
+	return func() {
+		// Set the write deadline of w to the past
+		if e := w.SetWriteDeadline(time.Unix(1, 0)); errors.Is(e, os.ErrNoDeadline) {
+			// Close the read end, if w does not support deadlines
+			r.Close()
 		}
-		// Sleep for defined delay
-		time.Sleep(stdin.d.Get())
 	}
 }
+
+// unconsumed returns an error wrapping ErrUnconsumed with the number of bytes of the input never consumed. The number
+// includes n bytes not written of the current line and the bytes left in the buffer of the pipe, if the platform reports
+// them. Input not yet read from the source is not included.
+func (stdin *MockStdin) unconsumed(n int64) error {
+	// Add the bytes left in the buffer of the pipe, if available
+	if b, e := buffered(stdin.r); e == nil {
+		n += b
+	}
+	// Return the error with the number of bytes never consumed
+	return fmt.Errorf("%w: %d bytes", ErrUnconsumed, n)
+}
+
+// eof ends the input of the mocked Stdin. It closes the write end of the pipe. A pseudo-terminal is
+// not closed, since it would hang up the terminal. Instead, the end-of-file character is sent. If the
+// last byte written is not a newline, the end-of-file character is sent twice: the first one ends
+// the pending line and the second one signals the end-of-file. After cancellation, the end-of-file
+// character is not sent, since the write deadline of the pseudo-terminal is exceeded.
+func (stdin *MockStdin) eof(last byte) error {
+	// Close the write end of the pipe, if not a pseudo-terminal
+	if !stdin.tty {
+		stdin.w.Close()
+		return nil
+	}
+	// End-of-file character sent to the pseudo-terminal
+	eof := []byte{ctrlD}
+	// Send the end-of-file character twice, if the last byte is not a newline
+	if last != '\n' {
+		eof = append(eof, ctrlD)
+	}
+	// Send the end-of-file character to the pseudo-terminal
+	_, e := stdin.w.Write(eof)
+	// Return an error, if any
+	return e
+}
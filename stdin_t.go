@@ -0,0 +1,153 @@
+// Stdin_t.go provides helpers for tests using a mocked Stdin. The helper StdinT arms and runs a new mocked Stdin,
+// registers Restore as cleanup of the test and reports errors of the mocked Stdin as test errors. LogWriter returns
+// a writer logging the echo of the mocked Stdin with the test log.
+//
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock
+
+// Import go standard library packages and tserr
+import (
+	"bytes"   // bytes
+	"context" // context
+	"errors"  // errors
+	"fmt"     // fmt
+	"io"      // io
+	"sync"    // sync
+	"testing" // testing
+
+	"github.com/thorstenrie/tserr" // tserr
+)
+
+// errParallel is reported, if StdinT is called from a parallel test or while another test is using StdinT.
+var errParallel = errors.New("os.Stdin is replaced globally and cannot be shared with parallel tests, remove t.Parallel() from the test using StdinT")
+
+// activeT holds the test currently using StdinT, if any.
+var activeT SafeVariable[testing.TB]
+
+// StdinT returns a new mocked Stdin configured with opts, armed with input and running. It replaces os.Stdin for the test t.
+// Restore is registered with t.Cleanup and its error, if any, is reported with t.Error. StdinT fails the test immediately, if
+// the mocked Stdin cannot be retrieved, armed or run. Since os.Stdin is replaced globally, StdinT fails the test immediately,
+// if t is a parallel test or another test is using StdinT at the same time, and a later call of t.Parallel panics.
+func StdinT(t testing.TB, input string, opts ...Option) *MockStdin {
+	// Panic if t is nil
+	if t == nil {
+		panic(tserr.NilPtr())
+	}
+	// Mark StdinT as helper function
+	t.Helper()
+	// Fail the test, if t is a parallel test
+	noParallel(t)
+	// Fail the test, if another test is using StdinT
+	acquireT(t)
+	// Retrieve a new mocked Stdin configured with opts
+	stdin, e := NewStdin(opts...)
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Arm the mocked Stdin with input
+	if e := stdin.SetString(input); e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "SetString", Fn: "Stdin", Err: e}))
+	}
+	// Retrieve a context canceled on cleanup
+	ctx, cancel := context.WithCancel(context.Background())
+	// Restore Stdin on cleanup and report its errors, if any
+	t.Cleanup(func() {
+		cancel()
+		if e := stdin.Restore(); e != nil {
+			t.Error(tserr.Op(&tserr.OpArgs{Op: "Restore", Fn: "Stdin", Err: e}))
+		}
+	})
+	// Run the mocked Stdin
+	if e := stdin.Run(ctx); e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Return the mocked Stdin
+	return stdin
+}
+
+// logWriter is an io.Writer, which logs each line with t.Log.
+type logWriter struct {
+	t   testing.TB // Test
+	buf []byte     // Incomplete line not logged yet
+	mu  sync.Mutex // Mutex
+}
+
+// LogWriter returns an io.Writer, which logs each written line with t.Log. An incomplete last line is logged on cleanup of the test.
+// It can be set as writer for the echo of the mocked Stdin with Echo, so that the echo is part of the test log instead of Stdout.
+func LogWriter(t testing.TB) io.Writer {
+	// Panic if t is nil
+	if t == nil {
+		panic(tserr.NilPtr())
+	}
+	// Retrieve a new log writer
+	w := &logWriter{t: t}
+	// Log an incomplete last line on cleanup
+	t.Cleanup(w.flush)
+	// Return the log writer
+	return w
+}
+
+// Write logs each complete line in p with t.Log and keeps an incomplete last line until the next write.
+func (w *logWriter) Write(p []byte) (int, error) {
+	// Lock the mutex
+	w.mu.Lock()
+	// Defer unlocking the mutex
+	defer w.mu.Unlock()
+	// Append p to the incomplete line
+	w.buf = append(w.buf, p...)
+	// Log each complete line
+	for i := bytes.IndexByte(w.buf, '\n'); i >= 0; i = bytes.IndexByte(w.buf, '\n') {
+		w.t.Log(string(bytes.TrimRight(w.buf[:i], "\r")))
+		w.buf = w.buf[i+1:]
+	}
+	// Return the number of bytes of p
+	return len(p), nil
+}
+
+// flush logs an incomplete last line, if any.
+func (w *logWriter) flush() {
+	// Lock the mutex
+	w.mu.Lock()
+	// Defer unlocking the mutex
+	defer w.mu.Unlock()
+	// Log the incomplete last line, if any
+	if len(w.buf) > 0 {
+		w.t.Log(string(w.buf))
+		w.buf = nil
+	}
+}
+
+// acquireT sets t as the test using StdinT. It fails the test immediately, if another test is using StdinT, for example a parallel test.
+func acquireT(t testing.TB) {
+	// Mark acquireT as helper function
+	t.Helper()
+	// Set t as the test using StdinT, if no other test is using StdinT
+	if e := activeT.Update(func(a testing.TB) (testing.TB, error) {
+		if a != nil {
+			return a, fmt.Errorf("%w: %s", errParallel, a.Name())
+		}
+		return t, nil
+	}); e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "StdinT", Fn: t.Name(), Err: e}))
+	}
+	// Release t as the test using StdinT on cleanup
+	t.Cleanup(func() { activeT.Set(nil) })
+}
+
+// noParallel fails the test immediately, if t is a parallel test. It uses t.Setenv, which panics in parallel tests and
+// prevents a later call of t.Parallel.
+func noParallel(t testing.TB) {
+	// Mark noParallel as helper function
+	t.Helper()
+	// Fail the test, if t.Setenv panics
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatal(tserr.Op(&tserr.OpArgs{Op: "StdinT", Fn: t.Name(), Err: errParallel}))
+		}
+	}()
+	// Set an environment variable for the duration of the test
+	t.Setenv("TSMOCK_STDIN", "1")
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock_test
+
+// Import go standard library packages as well as tserr and tsmock
+import (
+	"runtime" // runtime
+	"testing" // testing
+
+	"github.com/thorstenrie/tserr"  // tserr
+	"github.com/thorstenrie/tsmock" // tsmock
+)
+
+// fatalT is a test, which records whether it failed.
+type fatalT struct {
+	testing.TB      // Embedded test
+	failed     bool // True if the test failed
+}
+
+// Fatal records that the test failed and stops the go routine.
+func (f *fatalT) Fatal(args ...any) {
+	f.failed = true
+	runtime.Goexit()
+}
+
+// TestStdinT tests StdinT to provide a running mocked Stdin with the input. The test fails if the
+// input received from os.Stdin does not equal the contents.
+func TestStdinT(t *testing.T) {
+	// Retrieve a running mocked Stdin with the contents, which is restored on cleanup
+	tsmock.StdinT(t, contents, tsmock.WithVisibility(false))
+	// Scan stdin and compare the retrieved text with the contents
+	if e := testStdinEval(contents, t); e != nil {
+		t.Error(e)
+	}
+}
+
+// TestStdinTParallel tests StdinT to fail a test, if another test is using StdinT at the same time. The test fails
+// if StdinT does not fail the other test.
+func TestStdinTParallel(t *testing.T) {
+	// Retrieve a running mocked Stdin with the contents, which is restored on cleanup
+	tsmock.StdinT(t, contents, tsmock.WithVisibility(false))
+	// Retrieve another test, which records whether it failed
+	f := &fatalT{TB: t}
+	// Channel closed after StdinT returned or stopped the go routine
+	done := make(chan struct{})
+	// Call StdinT for the other test in a go routine
+	go func() {
+		defer close(done)
+		tsmock.StdinT(f, contents)
+	}()
+	// Wait for StdinT
+	<-done
+	// The test fails if StdinT did not fail the other test
+	if !f.failed {
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "StdinT", Actual: "not failed", Want: "failed"}))
+	}
+}
+
+// TestStdinTParallelTest tests StdinT to fail a test, which called t.Parallel. The test fails
+// if StdinT does not fail the parallel test.
+func TestStdinTParallelTest(t *testing.T) {
+	t.Run("parallel", func(t *testing.T) {
+		// Mark the test as parallel test
+		t.Parallel()
+		// Retrieve the parallel test, which records whether it failed
+		f := &fatalT{TB: t}
+		// Channel closed after StdinT returned or stopped the go routine
+		done := make(chan struct{})
+		// Call StdinT for the parallel test in a go routine
+		go func() {
+			defer close(done)
+			tsmock.StdinT(f, contents)
+		}()
+		// Wait for StdinT
+		<-done
+		// The test fails if StdinT did not fail the parallel test
+		if !f.failed {
+			t.Error(tserr.Return(&tserr.ReturnArgs{Op: "StdinT", Actual: "not failed", Want: "failed"}))
+		}
+	})
+}
@@ -5,7 +5,12 @@ package tsmock_test
 
 // Import go standard library packages as well as tserr, tsfio and tsmock
 import (
+	"bytes"   // bytes
 	"context" // context
+	"errors"  // errors
+	"runtime" // runtime
+	"strconv" // strconv
+	"strings" // strings
 	"testing" // testing
 	"time"    // time
 
@@ -29,15 +34,19 @@ var (
 // test file as reference string and as input to stdin. If the contents received from stdin does not equal
 // the contents of the test file the test fails. Also, the test fails in case of an error.
 func TestStdinV(t *testing.T) {
+	// Set the clock of stdin to a fake clock, which is advanced by testdelay while stdin is waiting
+	defer testFakeClock(t)()
 	if e := testStdin(context.Background(), true, testdelay, t); e != nil {
 		t.Error(e)
 	}
 }
 
-// TestStdinV tests Stdin with the test file with visibility set to false and input delay to testdelay. It will take the contents of the
+// TestStdinI tests Stdin with the test file with visibility set to false and input delay to testdelay. It will take the contents of the
 // test file as reference string and as input to stdin. If the contents received from stdin does not equal
 // the contents of the test file the test fails. Also, the test fails in case of an error.
 func TestStdinI(t *testing.T) {
+	// Set the clock of stdin to a fake clock, which is advanced by testdelay while stdin is waiting
+	defer testFakeClock(t)()
 	if e := testStdin(context.Background(), false, testdelay, t); e != nil {
 		t.Error(e)
 	}
@@ -60,12 +69,9 @@ func TestStdinTimeout(t *testing.T) {
 // TestStdinRestore tests Restore to cancel a running execution of a mocked Stdin. The test fails
 // if Restore returns an error of Stdin has an error in Err.
 func TestStdinRestore(t *testing.T) {
-	// Read reference data and open a stdin test input file for testing.
-	// Visibility of the input is set to v. The input delay is set to d.
-	// The input file of stdin is set to the stdin test input file
-	_, fs := testStdinSetup(true, testdelay, t)
-	// Defer closing the retrieved file.
-	defer fs.Close()
+	// Set the input of stdin to the test contents.
+	// Visibility of the input is set to true. The input delay is set to testdelay.
+	testStdinSetup(true, testdelay, t)
 	// Mock Stdin
 	if e := tsmock.Stdin.Run(context.Background()); e != nil {
 		// The test fails if Run returns an error
@@ -79,41 +85,35 @@ func TestStdinRestore(t *testing.T) {
 	testStdinClose(t)
 }
 
-// TestStdinSet tests Set to return an error, when used while a mocked Stdin is executing.
-// The test fails if Set returns nil or Stdin has an error in Err.
+// TestStdinSet tests SetString to return an error, when used while a mocked Stdin is executing.
+// The test fails if SetString returns nil or Stdin has an error in Err.
 func TestStdinSet(t *testing.T) {
-	// Read reference data and open a stdin test input file for testing.
-	// Visibility of the input is set to v. The input delay is set to d.
-	// The input file of stdin is set to the stdin test input file
-	_, fs := testStdinSetup(true, testdelay, t)
-	// Defer closing the retrieved file.
-	defer fs.Close()
+	// Set the input of stdin to the test contents.
+	// Visibility of the input is set to true. The input delay is set to testdelay.
+	testStdinSetup(true, testdelay, t)
 	// Mock Stdin
 	if e := tsmock.Stdin.Run(context.Background()); e != nil {
 		// The test fails if Run returns an error
 		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
 	}
-	if e := tsmock.Stdin.Set(fs); e == nil {
-		// The test fails if Set returns nil
-		t.Error(tserr.NilFailed("Set"))
+	if e := tsmock.Stdin.SetString(contents); e == nil {
+		// The test fails if SetString returns nil
+		t.Error(tserr.NilFailed("SetString"))
 	}
 	// Restore Stdin. The test fails if Stdin has an error in Err.
 	testStdinClose(t)
 }
 
-// TestStdinSetAgain tests setting mocked stdin again. The test fails if Set returns an error or if any other
+// TestStdinSetAgain tests setting mocked stdin again. The test fails if SetString returns an error or if any other
 // error occurs during the execution.
 func TestStdinSetAgain(t *testing.T) {
-	// Read reference data and open a stdin test input file for testing.
-	// Visibility of the input is set to v. The input delay is set to d.
-	// The input file of stdin is set to the stdin test input file
-	_, fs := testStdinSetup(true, testdelay, t)
-	// Defer closing the retrieved file.
-	defer fs.Close()
+	// Set the input of stdin to the test contents.
+	// Visibility of the input is set to true. The input delay is set to testdelay.
+	testStdinSetup(true, testdelay, t)
 	// Set again
-	if e := tsmock.Stdin.Set(fs); e != nil {
-		// The test fails if Set returns nil
-		t.Error(tserr.Op(&tserr.OpArgs{Op: "Set", Fn: "Mocked Stdin", Err: e}))
+	if e := tsmock.Stdin.SetString(contents); e != nil {
+		// The test fails if SetString returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "SetString", Fn: "Mocked Stdin", Err: e}))
 	}
 	// Mock Stdin
 	if e := tsmock.Stdin.Run(context.Background()); e != nil {
@@ -127,16 +127,13 @@ func TestStdinSetAgain(t *testing.T) {
 // TestStdinRunWithoutSet tests Run to return an error if Run is called again while the previous mocked Stdin execution has not finished yet. The test
 // fails if Run returns nil. The test fails if any other error occurs.
 func TestStdinRunAgain(t *testing.T) {
-	// Read reference data and open a stdin test input file for testing.
-	// Visibility of the input is set to v. The input delay is set to d.
-	// The input file of stdin is set to the stdin test input file
-	_, fs := testStdinSetup(true, testdelay, t)
-	// Defer closing the retrieved file.
-	defer fs.Close()
+	// Set the input of stdin to the test contents.
+	// Visibility of the input is set to true. The input delay is set to testdelay.
+	testStdinSetup(true, testdelay, t)
 	// Set again
-	if e := tsmock.Stdin.Set(fs); e != nil {
-		// The test fails if Set returns nil
-		t.Error(tserr.Op(&tserr.OpArgs{Op: "Set", Fn: "Mocked Stdin", Err: e}))
+	if e := tsmock.Stdin.SetString(contents); e != nil {
+		// The test fails if SetString returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "SetString", Fn: "Mocked Stdin", Err: e}))
 	}
 	// Mock Stdin
 	if e := tsmock.Stdin.Run(context.Background()); e != nil {
@@ -177,3 +174,171 @@ func TestNilFile(t *testing.T) {
 		t.Error(tserr.NilFailed("Set"))
 	}
 }
+
+// TestNewStdin tests an isolated mocked Stdin retrieved with NewStdin and configured with options. The echo of the input
+// is written to a buffer. The test fails if the input received from os.Stdin or the echo does not equal the contents of
+// the test file or if any error occurs.
+func TestNewStdin(t *testing.T) {
+	// Write the contents to the testfile
+	tsfio.WriteSingleStr(testfile, contents)
+	// Defer removing the testfile
+	defer tsfio.RemoveFile(testfile)
+	// Open the testfile
+	fs, err := tsfio.OpenFile(testfile)
+	// The test fails if OpenFile returns an error
+	if err != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "OpenFile", Fn: string(testfile), Err: err}))
+	}
+	// Defer closing the testfile
+	defer fs.Close()
+	// Buffer for the echo of the input
+	var echo bytes.Buffer
+	// Retrieve a new mocked Stdin with options
+	stdin, e := tsmock.NewStdin(tsmock.WithInput(fs), tsmock.WithVisibility(true), tsmock.WithEcho(&echo), tsmock.WithDelay(0))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "options", Err: e}))
+	}
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Scan stdin and compare the retrieved text with the contents
+	if e := testStdinEval(contents, t); e != nil {
+		t.Error(e)
+	}
+	// Restore Stdin
+	if e := stdin.Restore(); e != nil {
+		// The test fails if Restore returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Restore", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if the echo does not equal the contents
+	if echo.String() != contents {
+		t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "echo", Want: contents, Actual: echo.String()}))
+	}
+}
+
+// TestNewStdinLocked tests that only one mocked Stdin instance at a time can replace os.Stdin. The test fails
+// if SetString of a second instance returns nil while the global instance replaces os.Stdin or if
+// SetString of the second instance returns an error after the global instance was restored.
+func TestNewStdinLocked(t *testing.T) {
+	// Set the input of the global stdin to the test contents
+	testStdinSetup(true, 0, t)
+	// Retrieve a new mocked Stdin
+	stdin, e := tsmock.NewStdin()
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// The test fails if SetString returns nil while the global instance replaces os.Stdin
+	if e := stdin.SetString(contents); e == nil {
+		t.Error(tserr.NilFailed("SetString"))
+	}
+	// Restore Stdin. The test fails if Stdin has an error in Err.
+	testStdinClose(t)
+	// The test fails if SetString returns an error after the global instance was restored
+	if e := stdin.SetString(contents); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "SetString", Fn: "Stdin", Err: e}))
+	}
+	// Restore the new instance
+	if e := stdin.Restore(); e != nil {
+		// The test fails if Restore returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Restore", Fn: "Stdin", Err: e}))
+	}
+}
+
+// TestNewStdinOption tests if NewStdin returns an error in case of a nil option or a failing option. The test
+// fails if NewStdin returns nil.
+func TestNewStdinOption(t *testing.T) {
+	// The test fails if NewStdin returns nil for a nil option
+	if _, e := tsmock.NewStdin(nil); e == nil {
+		t.Error(tserr.NilFailed("NewStdin"))
+	}
+	// The test fails if NewStdin returns nil for a negative delay
+	if _, e := tsmock.NewStdin(tsmock.WithDelay(-1)); e == nil {
+		t.Error(tserr.NilFailed("NewStdin"))
+	}
+	// The test fails if NewStdin returns nil for a nil option following an input
+	if _, e := tsmock.NewStdin(tsmock.WithString(contents), nil); e == nil {
+		t.Error(tserr.NilFailed("NewStdin"))
+	}
+	// The test fails if os.Stdin is still replaced by the failed instance
+	stdin, e := tsmock.NewStdin(tsmock.WithString(contents))
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Restore Stdin. The test fails if Restore returns an error.
+	if e := stdin.Restore(); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Restore", Fn: "Stdin", Err: e}))
+	}
+}
+
+// TestStdinCancelDelay tests that Restore cancels a running mocked Stdin without waiting for the delay to complete.
+// The test fails if Restore takes longer than a second with a delay of one minute or if any error occurs.
+func TestStdinCancelDelay(t *testing.T) {
+	// Set the input of stdin to the test contents with a delay of one minute
+	testStdinSetup(false, time.Minute, t)
+	// Mock Stdin
+	if e := tsmock.Stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Retrieve the start time
+	start := time.Now()
+	// Restore Stdin. The test fails if Stdin has an error in Err.
+	testStdinClose(t)
+	// The test fails if Restore waited for the delay
+	if d := time.Since(start); d > time.Second {
+		t.Error(tserr.Lower(&tserr.LowerArgs{Var: "Restore duration", Actual: int64(d), HigherBound: int64(time.Second)}))
+	}
+}
+
+// TestStdinRestoreBlocked tests Restore, if the program never reads the input and the write of the mocked Stdin is blocked by a full pipe.
+// The test fails if Restore does not return within a second or if Restore does not return an error wrapping ErrUnconsumed. On Linux, the
+// test fails if the error does not report all bytes written or being written as never consumed.
+func TestStdinRestoreBlocked(t *testing.T) {
+	// Input exceeding the buffer of the pipe
+	in := "Aragorn\n" + strings.Repeat(strings.Repeat("x", 1023)+"\n", 256)
+	// Channel signaling the echo of the first line
+	echo := make(signalWriter, 1)
+	// Retrieve a new mocked Stdin with the input and the echo writer
+	stdin, e := tsmock.NewStdin(tsmock.WithEcho(echo), tsmock.WithString(in))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Wait for the first line to be written
+	<-echo
+	// Wait for the write of the second line to be blocked
+	time.Sleep(100 * time.Millisecond)
+	// Restore Stdin in a go routine
+	res := make(chan error, 1)
+	go func() { res <- stdin.Restore() }()
+	// Retrieve the error of Restore
+	var err error
+	select {
+	case err = <-res:
+	// The test fails if Restore does not return within a second
+	case <-time.After(time.Second):
+		t.Fatal(tserr.Return(&tserr.ReturnArgs{Op: "Restore", Actual: "blocked", Want: "returned"}))
+	}
+	// The test fails if the error does not wrap ErrUnconsumed
+	if !errors.Is(err, tsmock.ErrUnconsumed) {
+		t.Fatal(tserr.Return(&tserr.ReturnArgs{Op: "Restore", Actual: "no ErrUnconsumed", Want: "ErrUnconsumed"}))
+	}
+	// The test fails if the error is not a *LineError
+	var le *tsmock.LineError
+	if !errors.As(err, &le) {
+		t.Fatal(tserr.Return(&tserr.ReturnArgs{Op: "Restore", Actual: "no *LineError", Want: "*LineError"}))
+	}
+	// The test fails if not all bytes up to the end of the blocked line are reported as never consumed on Linux
+	if n := strconv.FormatInt(le.Offset+int64(len(le.Text)), 10) + " bytes"; (runtime.GOOS == "linux") && !strings.Contains(err.Error(), n) {
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "Restore", Actual: err.Error(), Want: n}))
+	}
+}
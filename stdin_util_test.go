@@ -7,8 +7,6 @@ package tsmock_test
 import (
 	"bufio"   // bufio
 	"context" // context
-
-	// fmt
 	"os"      // os
 	"testing" // testing
 	"time"    // time
@@ -29,9 +27,7 @@ func testStdin(ctx context.Context, v bool, d time.Duration, t *testing.T) error
 	// Read reference data and open a stdin test input file for testing.
 	// Visibility of the input is set to v. The input delay is set to d.
 	// The input file of stdin is set to the stdin test input file
-	ref, fs := testStdinSetup(v, d, t)
-	// Defer closing the retrieved file.
-	defer fs.Close()
+	ref := testStdinSetup(v, d, t)
 	// Defer restoring Stdin. The test fails if Stdin has an error in Err.
 	defer testStdinClose(t)
 	// Mock Stdin
@@ -47,29 +43,20 @@ func testStdin(ctx context.Context, v bool, d time.Duration, t *testing.T) error
 	return nil
 }
 
-// testStdinSetup reads reference data and opens a stdin test input file for testing. Visibility of
-// the input is set with v and the input delay with d as well as the input file is set to the stdin test input file.
-// It returns the reference data as string and
-// the stdin test input file as *os.File. The test fails in case of an error.
-func testStdinSetup(v bool, d time.Duration, t *testing.T) (string, *os.File) {
+// testStdinSetup sets the input of stdin to the test contents. Visibility of
+// the input is set with v and the input delay with d. It returns the reference data as string.
+// The test fails in case of an error.
+func testStdinSetup(v bool, d time.Duration, t *testing.T) string {
 	// Panic if t is nil
 	if t == nil {
 		panic(tserr.NilPtr())
 	}
-	// Write the contents of the testfile to the testfile
-	tsfio.WriteSingleStr(testfile, contents)
-	// Retrieve reference data from test file contents
+	// Retrieve reference data from test contents
 	ref := contents
-	// Open the testfile
-	fs, err := tsfio.OpenFile(testfile)
-	// The test fails if OpenFile returns an error
-	if err != nil {
-		t.Error(tserr.Op(&tserr.OpArgs{Op: "OpenFile", Fn: string(testfile), Err: err}))
-	}
-	// Set stdin to fs
-	if e := tsmock.Stdin.Set(fs); e != nil {
-		// The test fails if Set returns an error
-		t.Error(tserr.Op(&tserr.OpArgs{Op: "Set", Fn: string(testfile), Err: e}))
+	// Set stdin to the test contents
+	if e := tsmock.Stdin.SetString(contents); e != nil {
+		// The test fails if SetString returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "SetString", Fn: "contents", Err: e}))
 	}
 	// Set visibility of stdin to v
 	tsmock.Stdin.Visibility(v)
@@ -78,8 +65,8 @@ func testStdinSetup(v bool, d time.Duration, t *testing.T) (string, *os.File) {
 		// The test fails if Delay returns an error
 		t.Error(tserr.Op(&tserr.OpArgs{Op: "Delay", Fn: "Stdin", Err: e}))
 	}
-	// Return the reference string and fs
-	return string(ref), fs
+	// Return the reference string
+	return ref
 }
 
 // testStdinEval scans stdin and compares retrieved text with the reference string ref.
@@ -99,7 +86,7 @@ func testStdinEval(ref string, t *testing.T) error {
 	}
 	// The test fails if the retrieved text does not equal to the reference string ref
 	if tsfio.NormNewlinesStr(test) != tsfio.NormNewlinesStr(ref) {
-		return tserr.EqualStr(&tserr.EqualStrArgs{Var: string(testfile), Want: ref, Actual: test})
+		return tserr.EqualStr(&tserr.EqualStrArgs{Var: "Stdin", Want: ref, Actual: test})
 	}
 	return nil
 }
@@ -119,8 +106,53 @@ func testStdinClose(t *testing.T) {
 		// The test fails if Restore returns an error
 		t.Error(tserr.Op(&tserr.OpArgs{Op: "Restore", Fn: "tsmock.Stdin", Err: e}))
 	}
-	// Remove testfile
-	if e := tsfio.RemoveFile(testfile); e != nil {
-		t.Error(tserr.Op(&tserr.OpArgs{Op: "Remove", Fn: string(testfile), Err: e}))
+}
+
+// testFakeClock sets the clock of stdin to a fake clock. It starts a go routine, which advances the fake clock by
+// testdelay each time stdin is waiting. It returns a function, which stops the go routine and resets the clock of
+// stdin to the real clock. The test fails if the clock cannot be set.
+func testFakeClock(t *testing.T) func() {
+	// Panic if t is nil
+	if t == nil {
+		panic(tserr.NilPtr())
+	}
+	// Retrieve a new fake clock
+	c := tsmock.NewFakeClock(time.Now())
+	// Set the clock of stdin to the fake clock
+	if e := tsmock.Stdin.Clock(c); e != nil {
+		// The test fails if Clock returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Clock", Fn: "Stdin", Err: e}))
+	}
+	// Retrieve a context to stop the go routine
+	ctx, cancel := context.WithCancel(context.Background())
+	// Advance the fake clock by testdelay each time stdin is waiting
+	go func() {
+		for c.BlockUntil(ctx, 1) == nil {
+			c.Advance(testdelay)
+		}
+	}()
+	// Return a function to stop the go routine and reset the clock
+	return func() {
+		// Stop the go routine
+		cancel()
+		// Reset the clock of stdin to the real clock
+		if e := tsmock.Stdin.Clock(nil); e != nil {
+			// The test fails if Clock returns an error
+			t.Error(tserr.Op(&tserr.OpArgs{Op: "Clock", Fn: "Stdin", Err: e}))
+		}
+	}
+}
+
+// signalWriter is an io.Writer, which signals each write on the channel without blocking.
+type signalWriter chan struct{}
+
+// Write signals the write on the channel and discards p.
+func (w signalWriter) Write(p []byte) (int, error) {
+	// Signal the write, if the signal is not pending yet
+	select {
+	case w <- struct{}{}:
+	default:
 	}
+	// Return the number of bytes of p
+	return len(p), nil
 }
@@ -0,0 +1,185 @@
+// Typing.go provides the simulation of typing for the mocked Stdin. With a typing model, the input is written character by
+// character with delays returned by the model. Models are provided for a constant number of characters per second, for
+// words per minute with jitter and for think time proportional to the length of a line. Models with jitter are seeded to
+// make the timing reproducible. All delays are awaited with the clock of the mocked Stdin.
+//
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock
+
+// Import go standard library packages and tserr
+import (
+	"context"      // context
+	"fmt"          // fmt
+	"math/rand"    // math/rand
+	"sync"         // sync
+	"time"         // time
+	"unicode/utf8" // unicode/utf8
+
+	"github.com/thorstenrie/tserr" // tserr
+)
+
+// TypingModel is the interface for the timing of simulated typing.
+type TypingModel interface {
+	// Think returns the delay before typing the line
+	Think(line string) time.Duration
+	// Key returns the delay before typing the character r
+	Key(r rune) time.Duration
+}
+
+// constantTyping types a constant number of characters per second.
+type constantTyping struct {
+	d time.Duration // Delay before each character
+}
+
+// wpmTyping types a number of words per minute with jitter.
+type wpmTyping struct {
+	d      time.Duration // Mean delay before each character
+	jitter float64       // Maximum deviation from the mean delay as fraction of the mean delay
+	rnd    *rand.Rand    // Random number generator
+	mu     sync.Mutex    // Mutex for the random number generator
+}
+
+// thinkTyping thinks before each line proportional to the length of the line and types with another model.
+type thinkTyping struct {
+	d time.Duration // Think time per character of the line
+	m TypingModel   // Model for typing the characters, no delay if nil
+}
+
+// NewConstantTyping returns a typing model, which types cps characters per second. It returns an error, if cps is not positive.
+func NewConstantTyping(cps float64) (TypingModel, error) {
+	// Return an error if cps is not positive
+	if cps <= 0 {
+		return nil, errRange("cps", cps, "greater than 0")
+	}
+	// Return the model
+	return &constantTyping{d: time.Duration(float64(time.Second) / cps)}, nil
+}
+
+// NewWPMTyping returns a typing model, which types wpm words per minute with a word of five characters. The delay before each
+// character deviates randomly from the mean delay by at most the fraction jitter of the mean delay. The random deviation is
+// reproducible with seed. It returns an error, if wpm is not positive or if jitter is not between 0 and 1.
+func NewWPMTyping(wpm, jitter float64, seed int64) (TypingModel, error) {
+	// Return an error if wpm is not positive
+	if wpm <= 0 {
+		return nil, errRange("wpm", wpm, "greater than 0")
+	}
+	// Return an error if jitter is not between 0 and 1
+	if (jitter < 0) || (jitter > 1) {
+		return nil, errRange("jitter", jitter, "between 0 and 1")
+	}
+	// Return the model with a mean delay for five characters per word
+	return &wpmTyping{d: time.Duration(float64(time.Minute) / (wpm * 5)), jitter: jitter, rnd: rand.New(rand.NewSource(seed))}, nil
+}
+
+// errRange returns an error for the argument n with the value v, which is not in the range r.
+func errRange(n string, v float64, r string) error {
+	return tserr.Check(&tserr.CheckArgs{F: n, Err: fmt.Errorf("value %v, but expected to be %s", v, r)})
+}
+
+// NewThinkTyping returns a typing model, which thinks before each line for d per character of the line and types the characters
+// with model m. If m is nil, the characters are typed without delay. It returns an error, if d is negative.
+func NewThinkTyping(d time.Duration, m TypingModel) (TypingModel, error) {
+	// Return an error if d is negative
+	if d < 0 {
+		return nil, tserr.Higher(&tserr.HigherArgs{Var: "d", Actual: int64(d), LowerBound: 0})
+	}
+	// Return the model
+	return &thinkTyping{d: d, m: m}, nil
+}
+
+// Think returns no delay.
+func (m *constantTyping) Think(line string) time.Duration {
+	return 0
+}
+
+// Key returns the constant delay.
+func (m *constantTyping) Key(r rune) time.Duration {
+	return m.d
+}
+
+// Think returns no delay.
+func (m *wpmTyping) Think(line string) time.Duration {
+	return 0
+}
+
+// Key returns the mean delay with a random deviation of at most the jitter.
+func (m *wpmTyping) Key(r rune) time.Duration {
+	// Lock the mutex
+	m.mu.Lock()
+	// Defer unlocking the mutex
+	defer m.mu.Unlock()
+	// Return the mean delay deviated by a random fraction between -jitter and jitter
+	return time.Duration(float64(m.d) * (1 + m.jitter*(2*m.rnd.Float64()-1)))
+}
+
+// Think returns the think time proportional to the number of characters of line.
+func (m *thinkTyping) Think(line string) time.Duration {
+	return time.Duration(utf8.RuneCountInString(line)) * m.d
+}
+
+// Key returns the delay of the model for typing the characters, or no delay if the model is nil.
+func (m *thinkTyping) Key(r rune) time.Duration {
+	// Return no delay, if the model is nil
+	if m.m == nil {
+		return 0
+	}
+	// Return the delay of the model
+	return m.m.Key(r)
+}
+
+// Typing sets the typing model of the mocked Stdin to m. With a typing model, each line is written character by character with the
+// delays of the model. The delay set with Delay is still applied between lines. If m is nil, each line is written at once, which is
+// the default. Typing returns an error if the mocked Stdin is executing.
+func (stdin *MockStdin) Typing(m TypingModel) error {
+	// Return an error if mocked Stdin is executing
+	if e := stdin.notRunning(); e != nil {
+		return e
+	}
+	// Set the typing model to m
+	stdin.typing.Set(m)
+	// Return nil
+	return nil
+}
+
+// WithTyping returns an option to set the typing model of the mocked Stdin to m. See Typing.
+func WithTyping(m TypingModel) Option {
+	return func(stdin *MockStdin) error {
+		return stdin.Typing(m)
+	}
+}
+
+// typeInput writes i into Stdin. With a typing model, i is written character by character with the delays of the model. It returns
+// the number of bytes written and an error, if writing fails. It returns the error of the context, if the context is canceled while typing.
+func (stdin *MockStdin) typeInput(ctx context.Context, i []byte) (int, error) {
+	// Retrieve the typing model
+	m := stdin.typing.Get()
+	// Write i at once, if no typing model is set
+	if m == nil {
+		return stdin.w.Write(i)
+	}
+	// Think before typing and return the error of the context, if the context is canceled
+	if !stdin.sleep(ctx, m.Think(string(i))) {
+		return 0, ctx.Err()
+	}
+	// Number of bytes written
+	n := 0
+	for n < len(i) {
+		// Retrieve the next character
+		r, s := utf8.DecodeRune(i[n:])
+		// Wait before typing the character and return the error of the context, if the context is canceled
+		if !stdin.sleep(ctx, m.Key(r)) {
+			return n, ctx.Err()
+		}
+		// Write the character
+		k, e := stdin.w.Write(i[n : n+s])
+		n += k
+		// Return an error if writing fails
+		if e != nil {
+			return n, e
+		}
+	}
+	// Return the number of bytes written
+	return n, nil
+}
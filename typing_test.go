@@ -0,0 +1,141 @@
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock_test
+
+// Import go standard library packages as well as tserr and tsmock
+import (
+	"context" // context
+	"os"      // os
+	"strings" // strings
+	"testing" // testing
+	"time"    // time
+
+	"github.com/thorstenrie/tserr"  // tserr
+	"github.com/thorstenrie/tsmock" // tsmock
+)
+
+// TestTyping tests typing with ten characters per second and a fake clock. The test fails if a single read of the program
+// does not retrieve exactly the next character after the clock advanced by the delay of a character.
+func TestTyping(t *testing.T) {
+	// Retrieve a new typing model with ten characters per second
+	m, e := tsmock.NewConstantTyping(10)
+	// The test fails if NewConstantTyping returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewConstantTyping", Fn: "10", Err: e}))
+	}
+	// Retrieve a new fake clock
+	c := tsmock.NewFakeClock(time.Time{})
+	// Retrieve a new mocked Stdin typing the input
+	stdin, e := tsmock.NewStdin(tsmock.WithVisibility(false), tsmock.WithClock(c), tsmock.WithTyping(m), tsmock.WithString("Gé\n"))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Defer restoring Stdin
+	defer stdin.Restore()
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Buffer larger than the input
+	buf := make([]byte, 16)
+	for _, r := range "Gé\n" {
+		// Wait for the delay of the next character
+		if e := c.BlockUntil(context.Background(), 1); e != nil {
+			t.Fatal(e)
+		}
+		// Advance the clock by the delay of a character
+		c.Advance(100 * time.Millisecond)
+		// Read the input. The test fails if Read returns an error.
+		n, e := os.Stdin.Read(buf)
+		if e != nil {
+			t.Fatal(tserr.Op(&tserr.OpArgs{Op: "Read", Fn: "Stdin", Err: e}))
+		}
+		// The test fails if the read does not retrieve exactly the next character
+		if string(buf[:n]) != string(r) {
+			t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "Read", Actual: string(buf[:n]), Want: string(r)}))
+		}
+	}
+}
+
+// TestWPMTyping tests typing with words per minute and jitter. The test fails if two models with the same seed return
+// different delays or if a delay deviates from the mean delay by more than the jitter.
+func TestWPMTyping(t *testing.T) {
+	// Retrieve two new typing models with 60 words per minute, a jitter of 0.5 and the same seed
+	m1, e1 := tsmock.NewWPMTyping(60, 0.5, 42)
+	m2, e2 := tsmock.NewWPMTyping(60, 0.5, 42)
+	// The test fails if NewWPMTyping returns an error
+	for _, e := range []error{e1, e2} {
+		if e != nil {
+			t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewWPMTyping", Fn: "60", Err: e}))
+		}
+	}
+	// Mean delay of a character for 60 words per minute with five characters per word
+	d := 200 * time.Millisecond
+	for i := 0; i < 100; i++ {
+		// Retrieve the delays of both models
+		d1, d2 := m1.Key('a'), m2.Key('a')
+		// The test fails if the delays are different
+		if d1 != d2 {
+			t.Fatal(tserr.Equal(&tserr.EqualArgs{Var: "Key", Actual: int64(d2), Want: int64(d1)}))
+		}
+		// The test fails if the delay is lower than the mean delay minus the jitter
+		if d1 < d/2 {
+			t.Error(tserr.Higher(&tserr.HigherArgs{Var: "Key", Actual: int64(d1), LowerBound: int64(d / 2)}))
+		}
+		// The test fails if the delay is higher than the mean delay plus the jitter
+		if d1 > d*3/2 {
+			t.Error(tserr.Lower(&tserr.LowerArgs{Var: "Key", Actual: int64(d1), HigherBound: int64(d * 3 / 2)}))
+		}
+	}
+	// The test fails if NewWPMTyping returns nil for a jitter higher than 1
+	if _, e := tsmock.NewWPMTyping(60, 2, 42); e == nil {
+		t.Error(tserr.NilFailed("NewWPMTyping"))
+	}
+}
+
+// TestTypingErrors tests the errors of the typing models for invalid arguments. The test fails if an error is nil or if its
+// message does not contain the value of the argument and the expected range.
+func TestTypingErrors(t *testing.T) {
+	// Retrieve the errors for invalid arguments with fractional values
+	_, e1 := tsmock.NewConstantTyping(-0.5)
+	_, e2 := tsmock.NewWPMTyping(60, 1.5, 0)
+	for _, c := range []struct {
+		e    error  // Error of the typing model
+		want string // Expected part of the error message
+	}{
+		{e1, "value -0.5, but expected to be greater than 0"},
+		{e2, "value 1.5, but expected to be between 0 and 1"},
+	} {
+		// The test fails if the error is nil
+		if c.e == nil {
+			t.Error(tserr.NilFailed("typing model"))
+			continue
+		}
+		// The test fails if the error message does not contain the value and the expected range
+		if !strings.Contains(c.e.Error(), c.want) {
+			t.Error(tserr.EqualStr(&tserr.EqualStrArgs{Var: "error", Actual: c.e.Error(), Want: c.want}))
+		}
+	}
+}
+
+// TestThinkTyping tests the think time proportional to the length of a line. The test fails if the think time does not equal
+// the number of characters of the line multiplied by the think time per character or if a character is delayed.
+func TestThinkTyping(t *testing.T) {
+	// Retrieve a new typing model thinking 10ms per character
+	m, e := tsmock.NewThinkTyping(10*time.Millisecond, nil)
+	// The test fails if NewThinkTyping returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewThinkTyping", Fn: "10ms", Err: e}))
+	}
+	// The test fails if the think time does not equal 40ms for four characters
+	if d := m.Think("Gé!\n"); d != 40*time.Millisecond {
+		t.Error(tserr.Equal(&tserr.EqualArgs{Var: "Think", Actual: int64(d), Want: int64(40 * time.Millisecond)}))
+	}
+	// The test fails if a character is delayed
+	if d := m.Key('a'); d != 0 {
+		t.Error(tserr.Equal(&tserr.EqualArgs{Var: "Key", Actual: int64(d), Want: 0}))
+	}
+}
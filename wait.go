@@ -0,0 +1,57 @@
+// Wait.go provides the synchronization on the end of a run of the mocked Stdin. The end of a run can be awaited with
+// Wait or the channel returned by Done. The result of the run is retrieved with Result.
+//
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock
+
+// Import go standard library packages bytes and context
+import (
+	"bytes"   // bytes
+	"context" // context
+)
+
+// Result holds the result of a run of the mocked Stdin. It reports whether the run finished, was canceled or failed
+// as well as the number of lines and bytes written.
+type Result struct {
+	Finished  bool  // True if all input has been written
+	Cancelled bool  // True if the run was canceled before all input has been written
+	Err       error // Error of the run, if it failed
+	Lines     int   // Number of lines written, including a last line without a newline
+	Bytes     int64 // Number of bytes written
+}
+
+// count adds the bytes and the newlines in p to the result.
+func (res *Result) count(p []byte) {
+	// Count the newlines in p
+	res.Lines += bytes.Count(p, []byte{'\n'})
+	// Count the bytes in p
+	res.Bytes += int64(len(p))
+}
+
+// Done returns a channel, which is closed when the current run of the mocked Stdin finished. If the mocked Stdin is not executing,
+// the returned channel is already closed.
+func (stdin *MockStdin) Done() <-chan struct{} {
+	// Return the channel of the current run
+	return stdin.done.Get()
+}
+
+// Wait waits until the current run of the mocked Stdin finished. It returns the error of the run, if any. It returns the error of the
+// context, if the context is done before the run finished. If the mocked Stdin is not executing, Wait returns the error of the last run.
+func (stdin *MockStdin) Wait(ctx context.Context) error {
+	select {
+	// Return the error of the context, if the context is done
+	case <-ctx.Done():
+		return ctx.Err()
+	// Return the error of the run, if the run finished
+	case <-stdin.Done():
+		return stdin.Result().Err
+	}
+}
+
+// Result returns the result of the last run of the mocked Stdin. If the mocked Stdin is executing, it returns the result of the previous run.
+func (stdin *MockStdin) Result() Result {
+	// Return the result of the last run
+	return stdin.res.Get()
+}
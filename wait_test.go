@@ -0,0 +1,133 @@
+// Copyright (c) 2023 thorstenrie.
+// All Rights Reserved. Use is governed with GNU Affero General Public License v3.0
+// that can be found in the LICENSE file.
+package tsmock_test
+
+// Import go standard library packages as well as tserr and tsmock
+import (
+	"context" // context
+	"errors"  // errors
+	"io"      // io
+	"os"      // os
+	"testing" // testing
+	"time"    // time
+
+	"github.com/thorstenrie/tserr"  // tserr
+	"github.com/thorstenrie/tsmock" // tsmock
+)
+
+// TestWait tests Wait to return after all input has been written. The test fails if Wait returns an error or if the
+// result does not report a finished run with the number of lines and bytes of the input.
+func TestWait(t *testing.T) {
+	// Run a new mocked Stdin with input in raw mode and wait for the run to finish
+	res := testWait("Aragorn\nGandalf", 0, t)
+	// The test fails if the run did not finish
+	if !res.Finished || res.Cancelled {
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "Result", Actual: "not finished", Want: "finished"}))
+	}
+	// The test fails if the number of lines does not equal 2
+	if res.Lines != 2 {
+		t.Error(tserr.Equal(&tserr.EqualArgs{Var: "Lines", Actual: int64(res.Lines), Want: 2}))
+	}
+	// The test fails if the number of bytes does not equal the length of the input
+	if res.Bytes != int64(len("Aragorn\nGandalf")) {
+		t.Error(tserr.Equal(&tserr.EqualArgs{Var: "Bytes", Actual: res.Bytes, Want: int64(len("Aragorn\nGandalf"))}))
+	}
+}
+
+// TestWaitCancel tests Wait to return after the run was canceled. The test fails if Wait returns an error or
+// if the result does not report a canceled run.
+func TestWaitCancel(t *testing.T) {
+	// Run a new mocked Stdin with a delay of one minute, cancel it and wait for the run to finish
+	res := testWait(contents, time.Minute, t)
+	// The test fails if the run was not canceled
+	if res.Finished || !res.Cancelled {
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "Result", Actual: "not canceled", Want: "canceled"}))
+	}
+	// The test fails if the number of lines does not equal 1
+	if res.Lines != 1 {
+		t.Error(tserr.Equal(&tserr.EqualArgs{Var: "Lines", Actual: int64(res.Lines), Want: 1}))
+	}
+}
+
+// TestWaitTimeout tests Wait to return the error of the context, if the context is done before the run finished.
+// The test fails if Wait does not return context.DeadlineExceeded.
+func TestWaitTimeout(t *testing.T) {
+	// Retrieve a new mocked Stdin with a delay of one minute
+	stdin, e := tsmock.NewStdin(tsmock.WithDelay(time.Minute), tsmock.WithVisibility(false), tsmock.WithString(contents))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Defer restoring Stdin
+	defer stdin.Restore()
+	// Mock Stdin
+	if e := stdin.Run(context.Background()); e != nil {
+		// The test fails if Run returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Retrieve a context with a timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	// Defer cancel function
+	defer cancel()
+	// The test fails if Wait does not return context.DeadlineExceeded
+	if e := stdin.Wait(ctx); !errors.Is(e, context.DeadlineExceeded) {
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "Wait", Actual: "not context.DeadlineExceeded", Want: "context.DeadlineExceeded"}))
+	}
+}
+
+// TestDoneIdle tests that Done returns a closed channel, if the mocked Stdin is not executing.
+// The test fails if the channel is not closed.
+func TestDoneIdle(t *testing.T) {
+	// Retrieve a new mocked Stdin
+	stdin, e := tsmock.NewStdin()
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	select {
+	case <-stdin.Done():
+	// The test fails if the channel is not closed
+	default:
+		t.Error(tserr.Return(&tserr.ReturnArgs{Op: "Done", Actual: "open channel", Want: "closed channel"}))
+	}
+}
+
+// testWait runs a new mocked Stdin in raw mode with input in and delay d. If d is not zero, the run is canceled after the first line.
+// It waits for the run to finish and returns the result of the run. The test fails in case of an error.
+func testWait(in string, d time.Duration, t *testing.T) tsmock.Result {
+	// Panic if t is nil
+	if t == nil {
+		panic(tserr.NilPtr())
+	}
+	// Retrieve a new mocked Stdin in raw mode
+	stdin, e := tsmock.NewStdin(tsmock.WithRaw(true), tsmock.WithDelay(d), tsmock.WithVisibility(false), tsmock.WithString(in))
+	// The test fails if NewStdin returns an error
+	if e != nil {
+		t.Fatal(tserr.Op(&tserr.OpArgs{Op: "NewStdin", Fn: "Stdin", Err: e}))
+	}
+	// Defer restoring Stdin
+	defer stdin.Restore()
+	// Retrieve a context with a cancel function
+	ctx, cancel := context.WithCancel(context.Background())
+	// Defer cancel function
+	defer cancel()
+	// Mock Stdin
+	if e := stdin.Run(ctx); e != nil {
+		// The test fails if Run returns an error
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Run", Fn: "Stdin", Err: e}))
+	}
+	// Cancel the run after the first line, if d is not zero
+	if d != 0 {
+		// Read the first line
+		io.ReadAtLeast(os.Stdin, make([]byte, 8), 1)
+		// Cancel the run
+		cancel()
+	}
+	// The test fails if Wait returns an error
+	if e := stdin.Wait(context.Background()); e != nil {
+		t.Error(tserr.Op(&tserr.OpArgs{Op: "Wait", Fn: "Stdin", Err: e}))
+	}
+	// Return the result of the run
+	return stdin.Result()
+}